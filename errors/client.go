@@ -1,3 +1,5 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
 package errors
 
 import (
@@ -28,6 +30,17 @@ func Unauthorized(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Re
 	return utils.NewErr(w, allOpts...)
 }
 
+// PaymentRequired sends a 402 Payment Required response with optional configuration
+// Example: errors.PaymentRequired(w, gecho.Send())
+func PaymentRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusPaymentRequired),
+		utils.WithMessage(utils.PaymentRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
 // Forbidden sends a 403 Forbidden response with optional configuration
 // Example: errors.Forbidden(w, gecho.WithMessage("Access denied"), gecho.Send())
 func Forbidden(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
@@ -61,6 +74,39 @@ func MethodNotAllowed(w http.ResponseWriter, opts ...utils.ResponseOption) *util
 	return utils.NewErr(w, allOpts...)
 }
 
+// NotAcceptable sends a 406 Not Acceptable response with optional configuration
+// Example: errors.NotAcceptable(w, gecho.Send())
+func NotAcceptable(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusNotAcceptable),
+		utils.WithMessage(utils.NotAcceptableMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// ProxyAuthRequired sends a 407 Proxy Authentication Required response with optional configuration
+// Example: errors.ProxyAuthRequired(w, gecho.Send())
+func ProxyAuthRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusProxyAuthRequired),
+		utils.WithMessage(utils.ProxyAuthRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// RequestTimeout sends a 408 Request Timeout response with optional configuration
+// Example: errors.RequestTimeout(w, gecho.Send())
+func RequestTimeout(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusRequestTimeout),
+		utils.WithMessage(utils.RequestTimeoutMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
 // Conflict sends a 409 Conflict response with optional configuration
 // Example: errors.Conflict(w, gecho.WithMessage("Resource already exists"), gecho.Send())
 func Conflict(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
@@ -72,7 +118,183 @@ func Conflict(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Respon
 	return utils.NewErr(w, allOpts...)
 }
 
-// Too ManyRequests sends a 429 Too Many Requests response with optional configuration
+// Gone sends a 410 Gone response with optional configuration
+// Example: errors.Gone(w, gecho.Send())
+func Gone(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusGone),
+		utils.WithMessage(utils.GoneMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// LengthRequired sends a 411 Length Required response with optional configuration
+// Example: errors.LengthRequired(w, gecho.Send())
+func LengthRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusLengthRequired),
+		utils.WithMessage(utils.LengthRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// PreconditionFailed sends a 412 Precondition Failed response with optional configuration
+// Example: errors.PreconditionFailed(w, gecho.Send())
+func PreconditionFailed(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusPreconditionFailed),
+		utils.WithMessage(utils.PreconditionFailedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// RequestEntityTooLarge sends a 413 Request Entity Too Large response with optional configuration
+// Example: errors.RequestEntityTooLarge(w, gecho.Send())
+func RequestEntityTooLarge(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusRequestEntityTooLarge),
+		utils.WithMessage(utils.RequestEntityTooLargeMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// RequestURITooLong sends a 414 Request URI Too Long response with optional configuration
+// Example: errors.RequestURITooLong(w, gecho.Send())
+func RequestURITooLong(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusRequestURITooLong),
+		utils.WithMessage(utils.RequestURITooLongMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// UnsupportedMediaType sends a 415 Unsupported Media Type response with optional configuration
+// Example: errors.UnsupportedMediaType(w, gecho.Send())
+func UnsupportedMediaType(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusUnsupportedMediaType),
+		utils.WithMessage(utils.UnsupportedMediaTypeMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// RequestedRangeNotSatisfiable sends a 416 Requested Range Not Satisfiable response with optional configuration
+// Example: errors.RequestedRangeNotSatisfiable(w, gecho.Send())
+func RequestedRangeNotSatisfiable(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusRequestedRangeNotSatisfiable),
+		utils.WithMessage(utils.RequestedRangeNotSatisfiableMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// ExpectationFailed sends a 417 Expectation Failed response with optional configuration
+// Example: errors.ExpectationFailed(w, gecho.Send())
+func ExpectationFailed(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusExpectationFailed),
+		utils.WithMessage(utils.ExpectationFailedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// Teapot sends a 418 I'm a teapot response with optional configuration
+// Example: errors.Teapot(w, gecho.Send())
+func Teapot(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusTeapot),
+		utils.WithMessage(utils.TeapotMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// MisdirectedRequest sends a 421 Misdirected Request response with optional configuration
+// Example: errors.MisdirectedRequest(w, gecho.Send())
+func MisdirectedRequest(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusMisdirectedRequest),
+		utils.WithMessage(utils.MisdirectedRequestMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// UnprocessableEntity sends a 422 Unprocessable Entity response with optional configuration
+// Example: errors.UnprocessableEntity(w, gecho.WithData(validationErrors), gecho.Send())
+func UnprocessableEntity(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusUnprocessableEntity),
+		utils.WithMessage(utils.UnprocessableEntityMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// Locked sends a 423 Locked response with optional configuration
+// Example: errors.Locked(w, gecho.Send())
+func Locked(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusLocked),
+		utils.WithMessage(utils.LockedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// FailedDependency sends a 424 Failed Dependency response with optional configuration
+// Example: errors.FailedDependency(w, gecho.Send())
+func FailedDependency(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusFailedDependency),
+		utils.WithMessage(utils.FailedDependencyMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// TooEarly sends a 425 Too Early response with optional configuration
+// Example: errors.TooEarly(w, gecho.Send())
+func TooEarly(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusTooEarly),
+		utils.WithMessage(utils.TooEarlyMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// UpgradeRequired sends a 426 Upgrade Required response with optional configuration
+// Example: errors.UpgradeRequired(w, gecho.Send())
+func UpgradeRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusUpgradeRequired),
+		utils.WithMessage(utils.UpgradeRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// PreconditionRequired sends a 428 Precondition Required response with optional configuration
+// Example: errors.PreconditionRequired(w, gecho.Send())
+func PreconditionRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusPreconditionRequired),
+		utils.WithMessage(utils.PreconditionRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// TooManyRequests sends a 429 Too Many Requests response with optional configuration
 // Example: errors.TooManyRequests(w, gecho.WithMessage("Rate limit exceeded"), gecho.Send())
 func TooManyRequests(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
 	allOpts := []utils.ResponseOption{
@@ -82,3 +304,25 @@ func TooManyRequests(w http.ResponseWriter, opts ...utils.ResponseOption) *utils
 	allOpts = append(allOpts, opts...)
 	return utils.NewErr(w, allOpts...)
 }
+
+// RequestHeaderFieldsTooLarge sends a 431 Request Header Fields Too Large response with optional configuration
+// Example: errors.RequestHeaderFieldsTooLarge(w, gecho.Send())
+func RequestHeaderFieldsTooLarge(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusRequestHeaderFieldsTooLarge),
+		utils.WithMessage(utils.RequestHeaderFieldsTooLargeMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// UnavailableForLegalReasons sends a 451 Unavailable For Legal Reasons response with optional configuration
+// Example: errors.UnavailableForLegalReasons(w, gecho.Send())
+func UnavailableForLegalReasons(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusUnavailableForLegalReasons),
+		utils.WithMessage(utils.UnavailableForLegalReasonsMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
@@ -107,3 +107,22 @@ func TestMethodNotAllowed(t *testing.T) {
 		t.Errorf("Expected message '%s', got '%s'", utils.MethodNotAllowedMessage, response.Message())
 	}
 }
+
+func TestUnprocessableEntity(t *testing.T) {
+	w := httptest.NewRecorder()
+	UnprocessableEntity(w, utils.Send())
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+
+	var response utils.NewResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Message() != utils.UnprocessableEntityMessage {
+		t.Errorf("Expected message '%s', got '%s'", utils.UnprocessableEntityMessage, response.Message())
+	}
+}
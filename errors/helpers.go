@@ -0,0 +1,53 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
+package errors
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// statusHelpers maps status codes to the helper that renders them
+var statusHelpers = map[int]func(http.ResponseWriter, ...utils.ResponseOption) *utils.Response{
+	http.StatusBadRequest:                    BadRequest,
+	http.StatusUnauthorized:                  Unauthorized,
+	http.StatusPaymentRequired:               PaymentRequired,
+	http.StatusForbidden:                     Forbidden,
+	http.StatusNotFound:                      NotFound,
+	http.StatusMethodNotAllowed:              MethodNotAllowed,
+	http.StatusNotAcceptable:                 NotAcceptable,
+	http.StatusProxyAuthRequired:             ProxyAuthRequired,
+	http.StatusRequestTimeout:                RequestTimeout,
+	http.StatusConflict:                      Conflict,
+	http.StatusGone:                          Gone,
+	http.StatusLengthRequired:                LengthRequired,
+	http.StatusPreconditionFailed:            PreconditionFailed,
+	http.StatusRequestEntityTooLarge:         RequestEntityTooLarge,
+	http.StatusRequestURITooLong:             RequestURITooLong,
+	http.StatusUnsupportedMediaType:          UnsupportedMediaType,
+	http.StatusRequestedRangeNotSatisfiable:  RequestedRangeNotSatisfiable,
+	http.StatusExpectationFailed:             ExpectationFailed,
+	http.StatusTeapot:                        Teapot,
+	http.StatusMisdirectedRequest:            MisdirectedRequest,
+	http.StatusUnprocessableEntity:           UnprocessableEntity,
+	http.StatusLocked:                        Locked,
+	http.StatusFailedDependency:              FailedDependency,
+	http.StatusTooEarly:                      TooEarly,
+	http.StatusUpgradeRequired:               UpgradeRequired,
+	http.StatusPreconditionRequired:          PreconditionRequired,
+	http.StatusTooManyRequests:               TooManyRequests,
+	http.StatusRequestHeaderFieldsTooLarge:   RequestHeaderFieldsTooLarge,
+	http.StatusUnavailableForLegalReasons:    UnavailableForLegalReasons,
+	http.StatusInternalServerError:           InternalServerError,
+	http.StatusNotImplemented:                NotImplemented,
+	http.StatusBadGateway:                    BadGateway,
+	http.StatusServiceUnavailable:            ServiceUnavailable,
+	http.StatusGatewayTimeout:                GatewayTimeout,
+	http.StatusHTTPVersionNotSupported:       HTTPVersionNotSupported,
+	http.StatusVariantAlsoNegotiates:         VariantAlsoNegotiates,
+	http.StatusInsufficientStorage:           InsufficientStorage,
+	http.StatusLoopDetected:                  LoopDetected,
+	http.StatusNotExtended:                   NotExtended,
+	http.StatusNetworkAuthenticationRequired: NetworkAuthenticationRequired,
+}
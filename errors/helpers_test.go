@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestStatusHelpers(t *testing.T) {
+	for status, helper := range statusHelpers {
+		w := httptest.NewRecorder()
+		helper(w, utils.Send())
+
+		if w.Code != status {
+			t.Errorf("Expected status code %d, got %d", status, w.Code)
+			continue
+		}
+
+		var response utils.NewResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Errorf("Failed to decode response for status %d: %v", status, err)
+			continue
+		}
+
+		if response.Success() || response.Status() != status || response.Message() == "" {
+			t.Errorf("Unexpected envelope for status %d: success %v, status %d, message %q",
+				status, response.Success(), response.Status(), response.Message())
+		}
+	}
+}
+
+func TestRespondUsesStatusHelper(t *testing.T) {
+	w := httptest.NewRecorder()
+	Respond(w, NewHTTPError(http.StatusGone, ""), utils.Send())
+
+	var response utils.NewResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if w.Code != http.StatusGone || response.Message() != utils.GoneMessage {
+		t.Errorf("Expected 410 with message '%s', got %d with '%s'", utils.GoneMessage, w.Code, response.Message())
+	}
+}
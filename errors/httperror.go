@@ -0,0 +1,180 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+	"github.com/MonkyMars/gecho/validation"
+)
+
+// HTTPError is an error that describes the HTTP response it should produce
+// Handlers can return it and let the error adapter render the matching envelope
+type HTTPError struct {
+	Status  int    // HTTP status code of the response
+	Message string // Public message, defaults to the message of the status helper
+	Code    string // Optional machine-readable error code
+	Details any    // Optional details, sent as response data
+	Err     error  // Wrapped cause, logged but never sent to the client
+}
+
+// NewHTTPError creates an HTTPError with the given status and public message
+// Example: return errors.NewHTTPError(http.StatusPaymentRequired, "Subscription expired")
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the wrapped cause
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode sets the machine-readable error code
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// WithDetails sets the details sent as response data
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// Wrap sets the underlying cause of the error
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+// BadRequestErr creates a 400 Bad Request error
+func BadRequestErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, message)
+}
+
+// UnauthorizedErr creates a 401 Unauthorized error
+func UnauthorizedErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, message)
+}
+
+// ForbiddenErr creates a 403 Forbidden error
+func ForbiddenErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusForbidden, message)
+}
+
+// NotFoundErr creates a 404 Not Found error for the named resource
+// Example: return errors.NotFoundErr("user") // "user not found"
+func NotFoundErr(resource string) *HTTPError {
+	if resource == "" {
+		return NewHTTPError(http.StatusNotFound, "")
+	}
+	return NewHTTPError(http.StatusNotFound, resource+" not found")
+}
+
+// MethodNotAllowedErr creates a 405 Method Not Allowed error for the given method
+func MethodNotAllowedErr(method string) *HTTPError {
+	return NewHTTPError(http.StatusMethodNotAllowed, fmt.Sprintf("Method %s not allowed", method))
+}
+
+// ConflictErr creates a 409 Conflict error
+func ConflictErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusConflict, message)
+}
+
+// UnprocessableEntityErr creates a 422 Unprocessable Entity error
+func UnprocessableEntityErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusUnprocessableEntity, message)
+}
+
+// TooManyRequestsErr creates a 429 Too Many Requests error
+func TooManyRequestsErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusTooManyRequests, message)
+}
+
+// InternalErr creates a 500 Internal Server Error wrapping the cause
+func InternalErr(err error) *HTTPError {
+	return NewHTTPError(http.StatusInternalServerError, "").Wrap(err)
+}
+
+// ServiceUnavailableErr creates a 503 Service Unavailable error
+func ServiceUnavailableErr(message string) *HTTPError {
+	return NewHTTPError(http.StatusServiceUnavailable, message)
+}
+
+// AsHTTPError returns the HTTPError in err's chain, or the translation of a well-known error
+// Any other error is wrapped in a 500 Internal Server Error
+func AsHTTPError(err error) *HTTPError {
+	if err == nil {
+		return nil
+	}
+
+	var httpErr *HTTPError
+	if stderrors.As(err, &httpErr) {
+		return httpErr
+	}
+	if translated, ok := Translate(err); ok {
+		return translated
+	}
+	return InternalErr(err)
+}
+
+// Respond builds the error response for err using the matching status helper
+// Messages of wrapped causes are never sent, and plain errors become a generic 500
+// Server errors attach err with utils.WithError, so it is logged under a reference ID
+// Example: errors.Respond(w, err, gecho.WithRequest(r), gecho.Send())
+func Respond(w http.ResponseWriter, err error, opts ...utils.ResponseOption) *utils.Response {
+	httpErr := AsHTTPError(err)
+	if httpErr == nil {
+		httpErr = InternalErr(nil)
+	}
+
+	allOpts := make([]utils.ResponseOption, 0, len(opts)+4)
+	if httpErr.Status >= 500 {
+		allOpts = append(allOpts, utils.WithError(err))
+	}
+	if httpErr.Message != "" {
+		allOpts = append(allOpts, utils.WithMessage(httpErr.Message))
+	}
+
+	if httpErr.Code != "" {
+		data := map[string]any{"code": httpErr.Code}
+		if httpErr.Details != nil {
+			data["details"] = httpErr.Details
+		}
+		allOpts = append(allOpts, utils.WithData(data))
+	} else if httpErr.Details != nil {
+		allOpts = append(allOpts, utils.WithData(httpErr.Details))
+	}
+	allOpts = append(allOpts, opts...)
+
+	if helper, ok := statusHelpers[httpErr.Status]; ok {
+		return helper(w, allOpts...)
+	}
+
+	allOpts = append([]utils.ResponseOption{
+		utils.WithStatus(httpErr.Status),
+		utils.WithMessage(http.StatusText(httpErr.Status)),
+	}, allOpts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// ValidationErr creates a 422 Unprocessable Entity error listing the failed fields
+// The field errors are sent as {"errors": [...]} data
+func ValidationErr(errs validation.Errors) *HTTPError {
+	return UnprocessableEntityErr(utils.ValidationFailedMessage).
+		WithDetails(map[string]any{"errors": errs}).
+		Wrap(errs)
+}
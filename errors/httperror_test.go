@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestHTTPErrorUnwrap(t *testing.T) {
+	cause := stderrors.New("connection refused")
+	err := fmt.Errorf("loading user: %w", InternalErr(cause))
+
+	httpErr := AsHTTPError(err)
+	if httpErr.Status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, httpErr.Status)
+	}
+
+	if !stderrors.Is(err, cause) {
+		t.Errorf("Expected error chain to contain the cause")
+	}
+}
+
+func TestAsHTTPErrorPlainError(t *testing.T) {
+	httpErr := AsHTTPError(stderrors.New("boom"))
+	if httpErr.Status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, httpErr.Status)
+	}
+
+	if AsHTTPError(nil) != nil {
+		t.Errorf("Expected nil HTTPError for nil error")
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{"NotFound", NotFoundErr("user"), http.StatusNotFound, "user not found"},
+		{"DefaultMessage", NewHTTPError(http.StatusForbidden, ""), http.StatusForbidden, utils.ForbiddenMessage},
+		{"PlainErrorHidden", stderrors.New("secret database dsn"), http.StatusInternalServerError, utils.InternalServerErrorMessage},
+		{"WrappedCauseHidden", InternalErr(stderrors.New("secret")), http.StatusInternalServerError, utils.InternalServerErrorMessage},
+		{"StatusWithoutHelper", NewHTTPError(http.StatusPaymentRequired, "Subscription expired"), http.StatusPaymentRequired, "Subscription expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Respond(w, tt.err, utils.Send())
+
+			resp := w.Result()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			var response utils.NewResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.Message() != tt.expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", tt.expectedMessage, response.Message())
+			}
+		})
+	}
+}
+
+func TestRespondCodeAndDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	Respond(w, ConflictErr("Email taken").WithCode("email_taken").WithDetails("alice@example.com"), utils.Send())
+
+	var response utils.NewResponse
+	if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	dataMap, ok := response.Data().(map[string]any)
+	if !ok || dataMap["code"] != "email_taken" || dataMap["details"] != "alice@example.com" {
+		t.Errorf("Expected data with code and details, got '%v'", response.Data())
+	}
+}
+
+func TestRespondErrorReference(t *testing.T) {
+	var buf bytes.Buffer
+	logger := utils.NewLogger(utils.NewConfig(utils.WithOutput(&buf), utils.WithErrorOutput(&buf)))
+
+	w := httptest.NewRecorder()
+	Respond(w, InternalErr(stderrors.New("secret dsn")), utils.WithErrorLogger(logger), utils.Send())
+
+	var response utils.NewResponse
+	if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	info, ok := response.Meta()[utils.ErrorMetaField].(map[string]any)
+	if !ok || info["id"] == "" {
+		t.Fatalf("Expected an error reference ID, got %v", response.Meta())
+	}
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Errorf("Expected the cause to be hidden, got %s", w.Body.String())
+	}
+	if !strings.Contains(buf.String(), info["id"].(string)) || !strings.Contains(buf.String(), "secret dsn") {
+		t.Errorf("Expected the cause to be logged under the reference ID, got %s", buf.String())
+	}
+
+	w = httptest.NewRecorder()
+	Respond(w, NotFoundErr("user"), utils.Send())
+	if strings.Contains(w.Body.String(), `"meta"`) {
+		t.Errorf("Expected client errors without a reference ID, got %s", w.Body.String())
+	}
+}
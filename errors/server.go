@@ -1,3 +1,5 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
 package errors
 
 import (
@@ -17,6 +19,28 @@ func InternalServerError(w http.ResponseWriter, opts ...utils.ResponseOption) *u
 	return utils.NewErr(w, allOpts...)
 }
 
+// NotImplemented sends a 501 Not Implemented response with optional configuration
+// Example: errors.NotImplemented(w, gecho.Send())
+func NotImplemented(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusNotImplemented),
+		utils.WithMessage(utils.NotImplementedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// BadGateway sends a 502 Bad Gateway response with optional configuration
+// Example: errors.BadGateway(w, gecho.Send())
+func BadGateway(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusBadGateway),
+		utils.WithMessage(utils.BadGatewayMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
 // ServiceUnavailable sends a 503 Service Unavailable response with optional configuration
 // Example: errors.ServiceUnavailable(w, gecho.WithMessage("Maintenance mode"), gecho.Send())
 func ServiceUnavailable(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
@@ -27,3 +51,80 @@ func ServiceUnavailable(w http.ResponseWriter, opts ...utils.ResponseOption) *ut
 	allOpts = append(allOpts, opts...)
 	return utils.NewErr(w, allOpts...)
 }
+
+// GatewayTimeout sends a 504 Gateway Timeout response with optional configuration
+// Example: errors.GatewayTimeout(w, gecho.Send())
+func GatewayTimeout(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusGatewayTimeout),
+		utils.WithMessage(utils.GatewayTimeoutMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// HTTPVersionNotSupported sends a 505 HTTP Version Not Supported response with optional configuration
+// Example: errors.HTTPVersionNotSupported(w, gecho.Send())
+func HTTPVersionNotSupported(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusHTTPVersionNotSupported),
+		utils.WithMessage(utils.HTTPVersionNotSupportedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// VariantAlsoNegotiates sends a 506 Variant Also Negotiates response with optional configuration
+// Example: errors.VariantAlsoNegotiates(w, gecho.Send())
+func VariantAlsoNegotiates(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusVariantAlsoNegotiates),
+		utils.WithMessage(utils.VariantAlsoNegotiatesMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// InsufficientStorage sends a 507 Insufficient Storage response with optional configuration
+// Example: errors.InsufficientStorage(w, gecho.Send())
+func InsufficientStorage(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusInsufficientStorage),
+		utils.WithMessage(utils.InsufficientStorageMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// LoopDetected sends a 508 Loop Detected response with optional configuration
+// Example: errors.LoopDetected(w, gecho.Send())
+func LoopDetected(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusLoopDetected),
+		utils.WithMessage(utils.LoopDetectedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// NotExtended sends a 510 Not Extended response with optional configuration
+// Example: errors.NotExtended(w, gecho.Send())
+func NotExtended(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusNotExtended),
+		utils.WithMessage(utils.NotExtendedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+
+// NetworkAuthenticationRequired sends a 511 Network Authentication Required response with optional configuration
+// Example: errors.NetworkAuthenticationRequired(w, gecho.Send())
+func NetworkAuthenticationRequired(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusNetworkAuthenticationRequired),
+		utils.WithMessage(utils.NetworkAuthenticationRequiredMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
@@ -0,0 +1,147 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+
+	"github.com/MonkyMars/gecho/utils"
+	"github.com/MonkyMars/gecho/validation"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client went away
+// before the response was written, as popularized by nginx
+const StatusClientClosedRequest = 499
+
+// Translator converts an error into an HTTPError, ok is false when it does not apply
+type Translator func(err error) (httpErr *HTTPError, ok bool)
+
+// translators holds the registered translators, custom ones run before the built-ins
+var translators = struct {
+	mu      sync.RWMutex
+	custom  []Translator
+	builtin []Translator
+}{
+	builtin: []Translator{
+		translateIs(sql.ErrNoRows, http.StatusNotFound, ""),
+		translateIs(fs.ErrNotExist, http.StatusNotFound, ""),
+		translateIs(context.DeadlineExceeded, http.StatusGatewayTimeout, utils.GatewayTimeoutMessage),
+		translateIs(context.Canceled, StatusClientClosedRequest, utils.ClientClosedRequestMessage),
+		translateMaxBytes,
+		translateJSONSyntax,
+		translateJSONType,
+		translateIs(io.ErrUnexpectedEOF, http.StatusBadRequest, utils.MalformedBodyMessage),
+		translateValidation,
+	},
+}
+
+// RegisterTranslator registers a translator for domain errors
+// Translators run in registration order, before the built-in ones
+// A translator returning a nil HTTPError does not apply, the next one is tried
+func RegisterTranslator(t Translator) {
+	translators.mu.Lock()
+	defer translators.mu.Unlock()
+	translators.custom = append(translators.custom, t)
+}
+
+// RegisterErrorIs translates errors matching target with errors.Is into the given status and message
+// Example: errors.RegisterErrorIs(ErrUserBanned, http.StatusForbidden, "User is banned")
+func RegisterErrorIs(target error, status int, message string) {
+	RegisterTranslator(translateIs(target, status, message))
+}
+
+// RegisterErrorAs translates errors whose chain contains a T, found with errors.As
+// Example: errors.RegisterErrorAs(func(e *QuotaError) *errors.HTTPError { return errors.TooManyRequestsErr(e.Error()) })
+func RegisterErrorAs[T error](fn func(T) *HTTPError) {
+	RegisterTranslator(func(err error) (*HTTPError, bool) {
+		var target T
+		if !stderrors.As(err, &target) {
+			return nil, false
+		}
+		httpErr := fn(target)
+		return httpErr, httpErr != nil
+	})
+}
+
+// Translate converts err into an HTTPError using the registered translators
+// ok is false when no translator applies
+func Translate(err error) (*HTTPError, bool) {
+	translators.mu.RLock()
+	defer translators.mu.RUnlock()
+
+	for _, list := range [][]Translator{translators.custom, translators.builtin} {
+		for _, t := range list {
+			if httpErr, ok := t(err); ok && httpErr != nil {
+				// Copy so translators may return shared values
+				translated := *httpErr
+				if translated.Err == nil {
+					translated.Err = err
+				}
+				return &translated, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// translateIs returns a translator matching target with errors.Is
+func translateIs(target error, status int, message string) Translator {
+	return func(err error) (*HTTPError, bool) {
+		if !stderrors.Is(err, target) {
+			return nil, false
+		}
+		return NewHTTPError(status, message), true
+	}
+}
+
+// translateMaxBytes translates bodies exceeding http.MaxBytesReader limits into 413
+func translateMaxBytes(err error) (*HTTPError, bool) {
+	var maxBytesErr *http.MaxBytesError
+	if !stderrors.As(err, &maxBytesErr) {
+		return nil, false
+	}
+	return NewHTTPError(http.StatusRequestEntityTooLarge, "").
+		WithDetails(map[string]any{"limit": maxBytesErr.Limit}), true
+}
+
+// translateJSONSyntax translates malformed JSON into 400 with the offset of the error
+func translateJSONSyntax(err error) (*HTTPError, bool) {
+	var syntaxErr *json.SyntaxError
+	if !stderrors.As(err, &syntaxErr) {
+		return nil, false
+	}
+	return BadRequestErr(utils.MalformedBodyMessage).
+		WithDetails(map[string]any{
+			"offset": syntaxErr.Offset,
+			"reason": syntaxErr.Error(),
+		}), true
+}
+
+// translateJSONType translates JSON values of the wrong type into 400 with the offending field
+func translateJSONType(err error) (*HTTPError, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if !stderrors.As(err, &typeErr) {
+		return nil, false
+	}
+	return BadRequestErr(utils.InvalidFieldMessage).
+		WithDetails(map[string]any{
+			"field":  typeErr.Field,
+			"reason": fmt.Sprintf("expected %s but got %s", typeErr.Type, typeErr.Value),
+			"offset": typeErr.Offset,
+		}), true
+}
+
+// translateValidation translates failed struct validation into 422 with the field errors
+func translateValidation(err error) (*HTTPError, bool) {
+	var validationErrs validation.Errors
+	if !stderrors.As(err, &validationErrs) {
+		return nil, false
+	}
+	return ValidationErr(validationErrs), true
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTranslators(t *testing.T) {
+	var syntaxErr error
+	if err := json.Unmarshal([]byte(`{"name":`), new(map[string]any)); err != nil {
+		syntaxErr = err
+	}
+
+	var typeErr error
+	if err := json.Unmarshal([]byte(`{"age":"old"}`), new(struct {
+		Age int `json:"age"`
+	})); err != nil {
+		typeErr = err
+	}
+
+	var maxBytesErr error
+	body := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader("too long")), 2)
+	if _, err := io.ReadAll(body); err != nil {
+		maxBytesErr = err
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"NoRows", fmt.Errorf("query user: %w", sql.ErrNoRows), http.StatusNotFound},
+		{"NotExist", &fs.PathError{Op: "open", Path: "/missing", Err: fs.ErrNotExist}, http.StatusNotFound},
+		{"DeadlineExceeded", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"Canceled", context.Canceled, StatusClientClosedRequest},
+		{"MaxBytes", maxBytesErr, http.StatusRequestEntityTooLarge},
+		{"JSONSyntax", syntaxErr, http.StatusBadRequest},
+		{"JSONType", typeErr, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpErr, ok := Translate(tt.err)
+			if !ok {
+				t.Fatalf("Expected %v to be translated", tt.err)
+			}
+
+			if httpErr.Status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, httpErr.Status)
+			}
+
+			if !stderrors.Is(httpErr, tt.err) {
+				t.Errorf("Expected translated error to wrap the original error")
+			}
+		})
+	}
+}
+
+func TestJSONTypeDetails(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"age":"old"}`), new(struct {
+		Age int `json:"age"`
+	}))
+
+	httpErr := AsHTTPError(err)
+	details, ok := httpErr.Details.(map[string]any)
+	if !ok || details["field"] != "age" {
+		t.Errorf("Expected details with field 'age', got %v", httpErr.Details)
+	}
+}
+
+type quotaError struct {
+	limit int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("quota of %d exceeded", e.limit)
+}
+
+func TestRegisterTranslators(t *testing.T) {
+	errBanned := stderrors.New("user banned")
+	RegisterErrorIs(errBanned, http.StatusForbidden, "User is banned")
+	RegisterErrorAs(func(e *quotaError) *HTTPError {
+		return TooManyRequestsErr(e.Error()).WithDetails(map[string]int{"limit": e.limit})
+	})
+	defer func() {
+		translators.mu.Lock()
+		translators.custom = nil
+		translators.mu.Unlock()
+	}()
+
+	if httpErr := AsHTTPError(fmt.Errorf("login: %w", errBanned)); httpErr.Status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, httpErr.Status)
+	}
+
+	httpErr := AsHTTPError(fmt.Errorf("upload: %w", &quotaError{limit: 10}))
+	if httpErr.Status != http.StatusTooManyRequests || httpErr.Message != "quota of 10 exceeded" {
+		t.Errorf("Expected 429 with quota message, got %d '%s'", httpErr.Status, httpErr.Message)
+	}
+}
+
+func TestTranslatorReturningNil(t *testing.T) {
+	RegisterTranslator(func(err error) (*HTTPError, bool) {
+		return nil, true
+	})
+	defer func() {
+		translators.mu.Lock()
+		translators.custom = nil
+		translators.mu.Unlock()
+	}()
+
+	httpErr, ok := Translate(sql.ErrNoRows)
+	if !ok || httpErr.Status != http.StatusNotFound {
+		t.Errorf("Expected the next translator to apply, got %v %v", httpErr, ok)
+	}
+	if _, ok := Translate(stderrors.New("unknown")); ok {
+		t.Error("Expected no translation")
+	}
+}
+
+func TestHTTPErrorTakesPrecedence(t *testing.T) {
+	httpErr := AsHTTPError(BadRequestErr("Unknown account").Wrap(sql.ErrNoRows))
+	if httpErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected explicit HTTPError status %d, got %d", http.StatusBadRequest, httpErr.Status)
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -16,8 +15,8 @@ type User struct {
 }
 
 type CreateUserRequest struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Username string `json:"username" validate:"required,min=3,max=64"`
+	Email    string `json:"email" validate:"required,email"`
 }
 
 // In-memory user storage for demo
@@ -37,7 +36,7 @@ func main() {
 	// Create a new ServeMux for routing
 	mux := http.NewServeMux()
 	mux.HandleFunc("/users", usersHandler)
-	mux.HandleFunc("/users/", userByIDHandler)
+	mux.Handle("/users/", gecho.HandlerFunc(userByIDHandler))
 	mux.HandleFunc("/health", healthHandler)
 
 	// Wrap the mux with logging middleware
@@ -106,30 +105,8 @@ func listUsers(w http.ResponseWriter) {
 
 // Create a new user
 func createUser(w http.ResponseWriter, r *http.Request) {
-	var req CreateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		gecho.BadRequest(w,
-			gecho.WithMessage("Invalid request body"),
-			gecho.Send(),
-		)
-		return
-	}
-
-	// Validation
-	validationErrors := make(map[string]string)
-	if req.Username == "" {
-		validationErrors["username"] = "Username is required"
-	}
-	if req.Email == "" {
-		validationErrors["email"] = "Email is required"
-	}
-
-	if len(validationErrors) > 0 {
-		gecho.BadRequest(w,
-			gecho.WithMessage("Validation failed"),
-			gecho.WithData(validationErrors),
-			gecho.Send(),
-		)
+	req, ok := gecho.Bind[CreateUserRequest](w, r, gecho.DisallowUnknownFields())
+	if !ok {
 		return
 	}
 
@@ -161,35 +138,26 @@ func createUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // Get user by ID
-func userByIDHandler(w http.ResponseWriter, r *http.Request) {
-	// Only allow GET
-	if err := gecho.Handlers.HandleMethod(w, r, http.MethodGet); err != nil {
-		return
+func userByIDHandler(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return gecho.MethodNotAllowedErr(r.Method)
 	}
 
 	// Extract ID from path (simple parsing for demo)
 	var id int
-	_, err := fmt.Sscanf(r.URL.Path, "/users/%d", &id)
-	if err != nil {
-		gecho.BadRequest(w,
-			gecho.WithMessage("Invalid user ID"),
-			gecho.Send(),
-		)
-		return
+	if _, err := fmt.Sscanf(r.URL.Path, "/users/%d", &id); err != nil {
+		return gecho.BadRequestErr("Invalid user ID").Wrap(err)
 	}
 
 	// Find user
 	user, exists := users[id]
 	if !exists {
-		gecho.NotFound(w,
-			gecho.WithMessage(fmt.Sprintf("User with ID %d not found", id)),
-			gecho.Send(),
-		)
-		return
+		return gecho.NotFoundErr(fmt.Sprintf("User with ID %d", id))
 	}
 
 	gecho.Success(w,
 		gecho.WithData(user),
 		gecho.Send(),
 	)
+	return nil
 }
@@ -1,15 +1,27 @@
 package gecho
 
+//go:generate go run ./internal/genstatus
+
 import (
+	"iter"
+	"net/http"
+
 	"github.com/MonkyMars/gecho/errors"
 	"github.com/MonkyMars/gecho/handlers"
-	"github.com/MonkyMars/gecho/success"
+	"github.com/MonkyMars/gecho/redirect"
 	"github.com/MonkyMars/gecho/utils"
+	"github.com/MonkyMars/gecho/validation"
 )
 
 // Response types
 type Response = utils.Response
 type ResponseOption = utils.ResponseOption
+type ProblemDetails = utils.ProblemDetails
+type ErrorFormat = utils.ErrorFormat
+type Encoder = utils.Encoder
+type EncoderFunc = utils.EncoderFunc
+type Document = utils.Document
+type EnvelopeSchema = utils.EnvelopeSchema
 
 // Response option functions
 var WithData = utils.WithData
@@ -17,55 +29,301 @@ var WithMessage = utils.WithMessage
 var WithStatus = utils.WithStatus
 var WithHeader = utils.WithHeader
 var WithHeaders = utils.WithHeaders
+var WithRequest = utils.WithRequest
+var WithCookie = utils.WithCookie
 var Send = utils.Send
 
+// Response lifecycle
+type ResponseState = utils.ResponseState
+type HeaderTracker = utils.HeaderTracker
+
+const (
+	StateBuilt  = utils.StateBuilt
+	StateSent   = utils.StateSent
+	StateFailed = utils.StateFailed
+)
+
+var HeadersWritten = utils.HeadersWritten
+var (
+	ErrAlreadySent      = utils.ErrAlreadySent
+	ErrHeadersCommitted = utils.ErrHeadersCommitted
+	ErrEncoding         = utils.ErrEncoding
+)
+
+// Conditional request options
+var WithETag = utils.WithETag
+var WithAutoETag = utils.WithAutoETag
+var WithLastModified = utils.WithLastModified
+var CheckPreconditions = utils.CheckPreconditions
+
+// Problem details (RFC 9457) options
+var WithErrorFormat = utils.WithErrorFormat
+var WithProblemType = utils.WithProblemType
+var WithProblemTitle = utils.WithProblemTitle
+var WithInstance = utils.WithInstance
+var WithExtension = utils.WithExtension
+var SetErrorFormat = utils.SetErrorFormat
+var GetErrorFormat = utils.GetErrorFormat
+
+// Error formats
+var (
+	ErrorFormatEnvelope = utils.ErrorFormatEnvelope
+	ErrorFormatProblem  = utils.ErrorFormatProblem
+)
+
+// Error detail modes
+type ErrorMode = utils.ErrorMode
+
+const (
+	ErrorModeProduction  = utils.ErrorModeProduction
+	ErrorModeDevelopment = utils.ErrorModeDevelopment
+	ErrorMetaField       = utils.ErrorMetaField
+)
+
+var WithError = utils.WithError
+var WithErrorMode = utils.WithErrorMode
+var WithErrorLogger = utils.WithErrorLogger
+var SetErrorMode = utils.SetErrorMode
+var GetErrorMode = utils.GetErrorMode
+
 // Exported fluent API Functions
 var NewErr = utils.NewErr
 var NewOK = utils.NewOK
+var NewErrFor = utils.NewErrFor
+var NewOKFor = utils.NewOKFor
+
+// Envelope schema
+const OmitField = utils.OmitField
+
+var WithEnvelopeSchema = utils.WithEnvelopeSchema
+var SetEnvelopeSchema = utils.SetEnvelopeSchema
+var GetEnvelopeSchema = utils.GetEnvelopeSchema
+var DefaultEnvelopeSchema = utils.DefaultEnvelopeSchema
+
+// Content negotiation
+var RegisterEncoder = utils.RegisterEncoder
+var SetDefaultEncoder = utils.SetDefaultEncoder
+var RegisteredMediaTypes = utils.RegisteredMediaTypes
+var StatusAllowsBody = utils.StatusAllowsBody
+var JSONEncoder = utils.JSONEncoder
+var XMLEncoder = utils.XMLEncoder
+
+// Pagination
+type Page = utils.Page
+type PageParams = utils.PageParams
+type PageParamsOption = utils.PageParamsOption
+type CursorCodec = utils.CursorCodec
+
+var WithMeta = utils.WithMeta
+var WithPagination = utils.WithPagination
+var Paginated = utils.Paginated
+var ParsePageParams = utils.ParsePageParams
+var WithDefaultLimit = utils.WithDefaultLimit
+var WithMaxLimit = utils.WithMaxLimit
+var WithCursorCodec = utils.WithCursorCodec
+var NewCursorCodec = utils.NewCursorCodec
+var ErrInvalidCursor = utils.ErrInvalidCursor
+
+// Streaming
+type StreamFormat = utils.StreamFormat
+
+const MediaTypeNDJSON = utils.MediaTypeNDJSON
+
+var (
+	StreamFormatAuto     = utils.StreamFormatAuto
+	StreamFormatEnvelope = utils.StreamFormatEnvelope
+	StreamFormatNDJSON   = utils.StreamFormatNDJSON
+)
+
+var WithStreamFormat = utils.WithStreamFormat
+var WithFlushEvery = utils.WithFlushEvery
+
+// Server-Sent Events
+type EventStream = utils.EventStream
+type EventStreamOption = utils.EventStreamOption
+type Event = utils.Event
+type ReplayBuffer = utils.ReplayBuffer
+type RingBuffer = utils.RingBuffer
+
+var NewEventStream = utils.NewEventStream
+var NewRingBuffer = utils.NewRingBuffer
+var WithHeartbeat = utils.WithHeartbeat
+var WithRetry = utils.WithRetry
+var WithReplayBuffer = utils.WithReplayBuffer
+var WithEventLogger = utils.WithEventLogger
+var WithEventSchema = utils.WithEventSchema
+var ErrStreamClosed = utils.ErrStreamClosed
 
-// Exported Client Error Functions
-var BadRequest = errors.BadRequest
-var Unauthorized = errors.Unauthorized
-var Forbidden = errors.Forbidden
-var NotFound = errors.NotFound
-var MethodNotAllowed = errors.MethodNotAllowed
-var Conflict = errors.Conflict
-var TooManyRequests = errors.TooManyRequests
+// Stream writes the items of seq as they are produced, as an envelope or NDJSON
+func Stream[T any](w http.ResponseWriter, r *http.Request, seq iter.Seq2[T, error], opts ...ResponseOption) error {
+	return utils.Stream(w, r, seq, opts...)
+}
 
-// Exported Server Error Functions
-var InternalServerError = errors.InternalServerError
-var ServiceUnavailable = errors.ServiceUnavailable
+// Error values for error-returning handlers
+type HTTPError = errors.HTTPError
+type HandlerFunc = handlers.HandlerFunc
 
-// Exported Success Functions
-var Success = success.Success
-var Created = success.Created
+var NewHTTPError = errors.NewHTTPError
+var AsHTTPError = errors.AsHTTPError
+var RespondError = errors.Respond
+var BadRequestErr = errors.BadRequestErr
+var UnauthorizedErr = errors.UnauthorizedErr
+var ForbiddenErr = errors.ForbiddenErr
+var NotFoundErr = errors.NotFoundErr
+var MethodNotAllowedErr = errors.MethodNotAllowedErr
+var ConflictErr = errors.ConflictErr
+var UnprocessableEntityErr = errors.UnprocessableEntityErr
+var ValidationErr = errors.ValidationErr
+var TooManyRequestsErr = errors.TooManyRequestsErr
+var InternalErr = errors.InternalErr
+var ServiceUnavailableErr = errors.ServiceUnavailableErr
+
+// Error translation
+type Translator = errors.Translator
+
+const StatusClientClosedRequest = errors.StatusClientClosedRequest
+
+var RegisterTranslator = errors.RegisterTranslator
+var RegisterErrorIs = errors.RegisterErrorIs
+var TranslateError = errors.Translate
+
+// RegisterErrorAs translates errors whose chain contains a T, found with errors.As
+func RegisterErrorAs[T error](fn func(T) *HTTPError) {
+	errors.RegisterErrorAs(fn)
+}
+
+// Redirect location validation
+var SetAllowedHosts = redirect.SetAllowedHosts
+var SafeLocation = redirect.SafeLocation
+var ErrUnsafeLocation = redirect.ErrUnsafeLocation
 
 // Exported built-in handlers
 var Handlers = handlers.NewHandlers()
 
+// HandleHEAD serves HEAD requests with a GET handler, sending only the headers and Content-Length
+var HandleHEAD = Handlers.HandleHEAD
+
+// Request and correlation IDs
+type RequestIDOption = handlers.RequestIDOption
+type IDGenerator = utils.IDGenerator
+
+const (
+	RequestIDHeader     = utils.RequestIDHeader
+	CorrelationIDHeader = utils.CorrelationIDHeader
+)
+
+var HandleRequestID = Handlers.HandleRequestID
+var WithRequestIDHeader = handlers.WithRequestIDHeader
+var WithIDGenerator = handlers.WithIDGenerator
+var WithTrustIncoming = handlers.WithTrustIncoming
+var ContextWithRequestID = utils.ContextWithRequestID
+var RequestIDFrom = utils.RequestIDFrom
+var ContextWithCorrelationID = utils.ContextWithCorrelationID
+var CorrelationIDFrom = utils.CorrelationIDFrom
+var NewUUIDv7 = utils.NewUUIDv7
+var NewULID = utils.NewULID
+
+// Request body binding
+type BindOption = handlers.BindOption
+
+var WithMaxBodySize = handlers.WithMaxBodySize
+var DisallowUnknownFields = handlers.DisallowUnknownFields
+var WithContentTypes = handlers.WithContentTypes
+var WithValidationStatus = handlers.WithValidationStatus
+
+// Struct validation
+type ValidationErrors = validation.Errors
+type FieldError = validation.FieldError
+
+var ValidateStruct = validation.Struct
+
+// Bind decodes the JSON request body into a T, sending the error response when it fails
+func Bind[T any](w http.ResponseWriter, r *http.Request, opts ...BindOption) (T, bool) {
+	return handlers.Bind[T](w, r, opts...)
+}
+
 // Logger exports
 var NewLogger = utils.NewLogger
 var NewDefaultLogger = utils.NewDefaultLogger
+var SetDefaultLogger = utils.SetDefaultLogger
+var DefaultLogger = utils.DefaultLogger
 var DefaultLoggerConfig = utils.DefaultConfig
 var ParseLogLevel = utils.ParseLevel
 var Field = utils.Field
+var Ctx = utils.Ctx
+
+// Context-aware logging
+type ContextExtractor = utils.ContextExtractor
+
+var WithLogger = utils.WithLogger
+var LoggerFrom = utils.LoggerFrom
+var RegisterContextExtractor = utils.RegisterContextExtractor
+var ResetContextExtractors = utils.ResetContextExtractors
+
+// log/slog interoperability
+var NewLoggerFromSlog = utils.NewLoggerFromSlog
+var LevelFromSlog = utils.LevelFromSlog
+var SlogLevel = utils.SlogLevel
 var WithCallerSkip = utils.WithCallerSkip
 
+// Rotating log files
+type RotatingFile = utils.RotatingFile
+type RotateOption = utils.RotateOption
+
+var NewRotatingFile = utils.NewRotatingFile
+var WithMaxSize = utils.WithMaxSize
+var WithRotateEvery = utils.WithRotateEvery
+var WithMaxBackups = utils.WithMaxBackups
+var WithMaxAge = utils.WithMaxAge
+var WithCompress = utils.WithCompress
+var WithReopenOnSIGHUP = utils.WithReopenOnSIGHUP
+var ErrFileClosed = utils.ErrFileClosed
+
 // Logger config functions
 var NewConfig = utils.NewConfig
 var WithLogLevel = utils.WithLogLevel
 var WithLogFormat = utils.WithLogFormat
+var WithFormatter = utils.WithFormatter
 var WithColorize = utils.WithColorize
 var WithShowCaller = utils.WithShowCaller
 var WithTimeFormat = utils.WithTimeFormat
 var WithOutput = utils.WithOutput
 var WithErrorOutput = utils.WithErrorOutput
 var WithDefaultCallerSkip = utils.WithDefaultCallerSkip
+var WithAsync = utils.WithAsync
+var WithSinks = utils.WithSinks
+var WithLevelVar = utils.WithLevelVar
+var WithLevelRules = utils.WithLevelRules
+var WithEnvLevels = utils.WithEnvLevels
+
+// Asynchronous logging overflow policies
+type OverflowPolicy = utils.OverflowPolicy
+
+const (
+	OverflowBlock      = utils.OverflowBlock
+	OverflowDropNewest = utils.OverflowDropNewest
+	OverflowDropOldest = utils.OverflowDropOldest
+)
 
 // Logger types
 type Logger = utils.Logger
 type LoggerConfig = utils.Config
 type LoggerOptions = utils.LoggerOptions
+type LogOption = utils.Option
+type LogLevel = utils.Level
+type LogFormat = utils.Format
+type Sink = utils.Sink
+type SinkFilter = utils.SinkFilter
+
+// Runtime log levels
+type LevelVar = utils.LevelVar
+
+const LevelRulesEnv = utils.LevelRulesEnv
+
+var NewLevelVar = utils.NewLevelVar
+var ParseLevelRules = utils.ParseLevelRules
+var ValidateLevelPattern = utils.ValidateLevelPattern
+var HandleLogLevel = Handlers.HandleLogLevel
 
 // Log levels
 var (
@@ -76,6 +334,18 @@ var (
 	LogLevelFatal = utils.LevelFatal
 )
 
+// Log formatters
+type LogEntry = utils.Entry
+type FieldPair = utils.FieldPair
+type Formatter = utils.Formatter
+type TextFormatter = utils.TextFormatter
+type JSONFormatter = utils.JSONFormatter
+type PrettyFormatter = utils.PrettyFormatter
+
+var FormatterFor = utils.FormatterFor
+
+const DefaultPrettyTimeFormat = utils.DefaultPrettyTimeFormat
+
 // Log formats
 var (
 	LogFormatText   = utils.FormatText
@@ -0,0 +1,330 @@
+package gechoclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// Envelope is a decoded gecho response envelope with the data left undecoded
+type Envelope struct {
+	Status     int                   // Status from the envelope, or the HTTP status when the schema omits it
+	Success    bool                  // Success from the envelope, or whether the HTTP status is 2xx
+	Message    string                // Message from the envelope, or the detail of a problem document
+	Data       json.RawMessage       // Raw data, decoded into T by Do
+	Meta       json.RawMessage       // Raw meta block, such as pagination
+	RequestID  string                // Request ID from the envelope or the X-Request-ID header
+	Timestamp  time.Time             // Timestamp from the envelope, zero when omitted
+	StatusCode int                   // HTTP status code of the response
+	Header     http.Header           // Headers of the response
+	Problem    *utils.ProblemDetails // Set when the server responded with a problem details document
+}
+
+// Error is returned by Do for responses with success set to false
+type Error struct {
+	StatusCode int             // HTTP status code of the response
+	Message    string          // Message of the envelope
+	Data       json.RawMessage // Raw data of the envelope
+	Envelope   *Envelope       // The full decoded envelope
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("gecho: %d %s", e.StatusCode, e.Message)
+}
+
+// DecodeData decodes the data of the error response into v
+func (e *Error) DecodeData(v any) error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// Client sends requests to gecho servers, retrying rate limited and unavailable responses
+type Client struct {
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	schema     *utils.EnvelopeSchema
+}
+
+// Option is a function that configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client, defaults to http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times 429 and 503 responses are retried, defaults to 3
+func WithRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the exponential backoff range used when there is no Retry-After header
+// The maximum also caps the delay requested by a Retry-After header
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// WithEnvelopeSchema decodes responses using the given schema instead of the global one
+func WithEnvelopeSchema(schema utils.EnvelopeSchema) Option {
+	return func(c *Client) {
+		schema = schema.Normalize()
+		c.schema = &schema
+	}
+}
+
+// New creates a client with the given options
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		maxRetries: 3,
+		minBackoff: 100 * time.Millisecond,
+		maxBackoff: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// defaultClient is used by Do when no client is provided
+var defaultClient = New()
+
+// Do sends the request and decodes the envelope, with its data decoded into a T
+// Responses with success set to false are returned as an *Error along with the envelope
+// Example: user, env, err := gechoclient.Do[User](ctx, client, req)
+func Do[T any](ctx context.Context, c *Client, req *http.Request) (T, *Envelope, error) {
+	var result T
+	if c == nil {
+		c = defaultClient
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return result, nil, err
+	}
+	defer resp.Body.Close()
+
+	env, err := c.decodeEnvelope(resp)
+	if err != nil {
+		return result, nil, err
+	}
+
+	if !env.Success {
+		return result, env, &Error{
+			StatusCode: env.StatusCode,
+			Message:    env.Message,
+			Data:       env.Data,
+			Envelope:   env,
+		}
+	}
+
+	if len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, &result); err != nil {
+			return result, env, fmt.Errorf("gecho: decoding data: %w", err)
+		}
+	}
+
+	return result, env, nil
+}
+
+// acceptHeader is the Accept header sent when the caller has not set one
+var acceptHeader = utils.MediaTypeJSON + ", " + utils.ProblemContentType
+
+// maxDrainBytes is the most read from the body of a response that is retried
+const maxDrainBytes = 64 << 10
+
+// send performs the request, retrying 429 and 503 responses
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// The request of the caller is cloned, its headers are not changed
+	req = req.Clone(ctx)
+
+	// Envelopes and problem details are decoded as JSON, so servers negotiating other formats must send JSON
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", acceptHeader)
+	}
+
+	// Requests made while serving another one carry its correlation ID
+	if id := utils.CorrelationIDFrom(ctx); id != "" && req.Header.Get(utils.CorrelationIDHeader) == "" {
+		req.Header.Set(utils.CorrelationIDHeader, id)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		if !retryable || attempt >= c.maxRetries {
+			return resp, nil
+		}
+
+		// A body that cannot be replayed cannot be retried
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		// Draining a small body lets the connection be reused, a larger one is not worth reading
+		wait := c.retryDelay(resp, attempt)
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt
+// The Retry-After header is honored up to the maximum backoff, otherwise exponential backoff with jitter is used
+func (c *Client) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			// Compared in seconds, as a large value overflows a Duration
+			if time.Duration(seconds) > c.maxBackoff/time.Second {
+				return max(c.maxBackoff, 0)
+			}
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(time.Until(at), 0), max(c.maxBackoff, 0))
+		}
+	}
+
+	backoff := c.minBackoff << attempt
+	if backoff <= 0 || backoff > c.maxBackoff {
+		backoff = c.maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	// Full jitter within the upper half of the backoff window
+	half := backoff / 2
+	return half + rand.N(half+1)
+}
+
+// decodeEnvelope decodes a gecho envelope or a problem details document
+func (c *Client) decodeEnvelope(resp *http.Response) (*Envelope, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gecho: reading response: %w", err)
+	}
+
+	env := &Envelope{
+		Status:     resp.StatusCode,
+		Success:    resp.StatusCode >= 200 && resp.StatusCode < 300,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RequestID:  resp.Header.Get(utils.RequestIDHeader),
+	}
+
+	if len(body) == 0 {
+		env.Message = http.StatusText(resp.StatusCode)
+		return env, nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == utils.ProblemContentType {
+		var problem utils.ProblemDetails
+		if err := json.Unmarshal(body, &problem); err != nil {
+			return nil, fmt.Errorf("gecho: decoding problem details: %w", err)
+		}
+
+		env.Problem = &problem
+		env.Success = false
+		if problem.Status != 0 {
+			env.Status = problem.Status
+		}
+		env.Message = problem.Detail
+		if env.Message == "" {
+			env.Message = problem.Title
+		}
+		if len(problem.Extensions) > 0 {
+			env.Data, _ = json.Marshal(problem.Extensions)
+		}
+		return env, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		if !env.Success {
+			// Not a gecho envelope, report the HTTP error itself
+			env.Message = http.StatusText(resp.StatusCode)
+			return env, nil
+		}
+		return nil, fmt.Errorf("gecho: decoding envelope: %w", err)
+	}
+
+	schema := utils.GetEnvelopeSchema()
+	if c.schema != nil {
+		schema = *c.schema
+	}
+
+	targets := []struct {
+		name   string
+		target any
+	}{
+		{schema.StatusField, &env.Status},
+		{schema.SuccessField, &env.Success},
+		{schema.MessageField, &env.Message},
+		{schema.RequestIDField, &env.RequestID},
+		{schema.TimestampField, &env.Timestamp},
+	}
+	for _, t := range targets {
+		value, ok := raw[t.name]
+		if t.name == utils.OmitField || !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, t.target); err != nil {
+			return nil, fmt.Errorf("gecho: decoding envelope field %s: %w", t.name, err)
+		}
+	}
+
+	if schema.DataField != utils.OmitField {
+		env.Data = raw[schema.DataField]
+	}
+	if schema.MetaField != utils.OmitField {
+		env.Meta = raw[schema.MetaField]
+	}
+
+	return env, nil
+}
+
+// IsStatus reports whether err is an *Error with the given HTTP status code
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,277 @@
+package gechoclient
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.NewOK(w, utils.WithData(user{ID: 1, Name: "alice"}), utils.WithMessage("Found"), utils.Send())
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	result, env, err := Do[user](context.Background(), New(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.ID != 1 || result.Name != "alice" {
+		t.Errorf("Expected decoded user, got %+v", result)
+	}
+
+	if env.Status != http.StatusOK || !env.Success || env.Message != "Found" {
+		t.Errorf("Expected decoded envelope, got %+v", env)
+	}
+
+	if env.Timestamp.IsZero() {
+		t.Errorf("Expected timestamp to be decoded")
+	}
+}
+
+func TestDoErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.NewErr(w,
+			utils.WithStatus(http.StatusNotFound),
+			utils.WithMessage("user not found"),
+			utils.WithData(map[string]string{"id": "42"}),
+			utils.Send(),
+		)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, env, err := Do[user](context.Background(), nil, req)
+
+	var apiErr *Error
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("Expected *Error, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "user not found" {
+		t.Errorf("Expected 404 'user not found', got %d '%s'", apiErr.StatusCode, apiErr.Message)
+	}
+
+	var data map[string]string
+	if err := apiErr.DecodeData(&data); err != nil || data["id"] != "42" {
+		t.Errorf("Expected error data with id '42', got %v (%v)", data, err)
+	}
+
+	if env == nil || env.Success {
+		t.Errorf("Expected the failed envelope to be returned, got %+v", env)
+	}
+
+	if !IsStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected IsStatus to match 404")
+	}
+}
+
+func TestDoProblemDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.NewErr(w,
+			utils.WithStatus(http.StatusConflict),
+			utils.WithMessage("Email taken"),
+			utils.WithErrorFormat(utils.ErrorFormatProblem),
+			utils.Send(),
+		)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, env, err := Do[user](context.Background(), New(), req)
+
+	if !IsStatus(err, http.StatusConflict) {
+		t.Fatalf("Expected 409 error, got %v", err)
+	}
+
+	if env.Problem == nil || env.Message != "Email taken" {
+		t.Errorf("Expected problem details to be decoded, got %+v", env)
+	}
+}
+
+func TestDoEnvelopeSchema(t *testing.T) {
+	schema := utils.EnvelopeSchema{SuccessField: "ok", DataField: "result", StatusField: utils.OmitField}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.NewOK(w, utils.WithEnvelopeSchema(schema), utils.WithData(user{ID: 7}), utils.Send())
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	result, env, err := Do[user](context.Background(), New(WithEnvelopeSchema(schema)), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.ID != 7 || env.Status != http.StatusOK {
+		t.Errorf("Expected data from the renamed field and the HTTP status, got %+v %+v", result, env)
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("Expected the body to be replayed, got '%s'", body)
+		}
+
+		switch attempts.Add(1) {
+		case 1:
+			utils.NewErr(w, utils.WithStatus(http.StatusTooManyRequests), utils.WithHeader("Retry-After", "0"), utils.Send())
+		case 2:
+			utils.NewErr(w, utils.WithStatus(http.StatusServiceUnavailable), utils.Send())
+		default:
+			utils.NewOK(w, utils.WithData(user{ID: 3}), utils.Send())
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	client := New(WithRetries(3), WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+	result, _, err := Do[user](context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("Expected no error after retries, got %v", err)
+	}
+
+	if result.ID != 3 || attempts.Load() != 3 {
+		t.Errorf("Expected success on the third attempt, got %+v after %d attempts", result, attempts.Load())
+	}
+}
+
+func TestDoRetriesExhausted(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		utils.NewErr(w, utils.WithStatus(http.StatusServiceUnavailable), utils.Send())
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	client := New(WithRetries(2), WithBackoff(time.Millisecond, time.Millisecond))
+
+	if _, _, err := Do[user](context.Background(), client, req); !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Errorf("Expected 503 error, got %v", err)
+	}
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestDoRetryContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.NewErr(w, utils.WithStatus(http.StatusTooManyRequests), utils.WithHeader("Retry-After", "60"), utils.Send())
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, _, err := Do[user](ctx, New(), req); !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline to stop retries, got %v", err)
+	}
+}
+
+func TestRetryAfterClamped(t *testing.T) {
+	client := New(WithBackoff(time.Millisecond, 2*time.Second))
+	tests := []struct {
+		name       string
+		retryAfter string
+		expected   time.Duration
+	}{
+		{"Seconds", "1", time.Second},
+		{"SecondsAboveMax", "86400", 2 * time.Second},
+		{"Overflow", "9223372036854775807", 2 * time.Second},
+		{"Date", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat), 2 * time.Second},
+		{"PastDate", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Retry-After": {tt.retryAfter}}}
+			if delay := client.retryDelay(resp, 0); delay != tt.expected {
+				t.Errorf("Expected a delay of %v, got %v", tt.expected, delay)
+			}
+		})
+	}
+}
+
+func TestDoRequestIDs(t *testing.T) {
+	var correlation string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlation = r.Header.Get(utils.CorrelationIDHeader)
+		r = r.WithContext(utils.ContextWithRequestID(r.Context(), "downstream-1"))
+		utils.NewOKFor(w, r, utils.Send())
+	}))
+	defer server.Close()
+
+	ctx := utils.ContextWithCorrelationID(context.Background(), "journey-9")
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, env, err := Do[any](ctx, New(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if correlation != "journey-9" {
+		t.Errorf("Expected the correlation ID to be propagated, got %q", correlation)
+	}
+	if req.Header.Get(utils.CorrelationIDHeader) != "" {
+		t.Error("Expected the caller's request not to be modified")
+	}
+	if env.RequestID != "downstream-1" {
+		t.Errorf("Expected the request ID to be decoded, got %q", env.RequestID)
+	}
+}
+
+func TestDoAcceptsJSON(t *testing.T) {
+	utils.SetDefaultEncoder(utils.MediaTypeXML)
+	defer utils.SetDefaultEncoder(utils.MediaTypeJSON)
+
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		utils.NewOKFor(w, r, utils.WithData(user{ID: 1, Name: "alice"}), utils.Send())
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	got, _, err := Do[user](context.Background(), New(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Name != "alice" {
+		t.Errorf("Expected the data to be decoded, got %+v", got)
+	}
+	if accept != "application/json, application/problem+json" {
+		t.Errorf("Expected the client to accept JSON, got %q", accept)
+	}
+	if req.Header.Get("Accept") != "" {
+		t.Error("Expected the caller's request not to be modified")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept", "application/json")
+	if _, _, err := Do[user](context.Background(), New(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if accept != "application/json" {
+		t.Errorf("Expected the Accept header of the caller to be kept, got %q", accept)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/utils"
+	"github.com/MonkyMars/gecho/validation"
+)
+
+// DefaultMaxBodySize is the maximum request body size accepted by Bind, 1 MiB
+const DefaultMaxBodySize int64 = 1 << 20
+
+// BindOption is a function that configures Bind
+type BindOption func(*bindConfig)
+
+// bindConfig holds the configuration of Bind
+type bindConfig struct {
+	maxBodySize           int64
+	disallowUnknownFields bool
+	contentTypes          []string
+	validationStatus      int
+}
+
+// WithMaxBodySize sets the maximum request body size in bytes
+func WithMaxBodySize(size int64) BindOption {
+	return func(bc *bindConfig) {
+		bc.maxBodySize = size
+	}
+}
+
+// DisallowUnknownFields rejects bodies containing fields that do not exist in the target type
+func DisallowUnknownFields() BindOption {
+	return func(bc *bindConfig) {
+		bc.disallowUnknownFields = true
+	}
+}
+
+// WithContentTypes sets the accepted request media types, defaults to application/json
+func WithContentTypes(contentTypes ...string) BindOption {
+	return func(bc *bindConfig) {
+		bc.contentTypes = contentTypes
+	}
+}
+
+// WithValidationStatus sets the status sent when validation fails, 422 or 400
+func WithValidationStatus(status int) BindOption {
+	return func(bc *bindConfig) {
+		bc.validationStatus = status
+	}
+}
+
+// Bind decodes the JSON request body into a T and validates it using its `validate` tags
+// On failure the matching error response is sent and ok is false:
+// 415 for an unsupported Content-Type, 413 for a body that is too large,
+// 400 for malformed bodies, with the offending field and reason as data,
+// and 422 for failed validation, with the field errors as data
+// Example: req, ok := handlers.Bind[CreateUserRequest](w, r); if !ok { return }
+func Bind[T any](w http.ResponseWriter, r *http.Request, opts ...BindOption) (T, bool) {
+	var result T
+
+	config := &bindConfig{
+		maxBodySize:      DefaultMaxBodySize,
+		contentTypes:     []string{utils.MediaTypeJSON},
+		validationStatus: http.StatusUnprocessableEntity,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := bindBody(w, r, config, &result); err != nil {
+		errors.Respond(w, err, utils.WithRequest(r), utils.Send())
+		var zero T
+		return zero, false
+	}
+
+	if err := validation.Struct(&result); err != nil {
+		httpErr := errors.AsHTTPError(err)
+		var validationErrs validation.Errors
+		if stderrors.As(err, &validationErrs) {
+			httpErr.Status = config.validationStatus
+		}
+		errors.Respond(w, httpErr, utils.WithRequest(r), utils.Send())
+		var zero T
+		return zero, false
+	}
+
+	return result, true
+}
+
+// bindBody checks the Content-Type and decodes exactly one JSON value into v
+func bindBody(w http.ResponseWriter, r *http.Request, config *bindConfig, v any) error {
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(config.contentTypes, strings.ToLower(mediaType)) {
+		return errors.NewHTTPError(http.StatusUnsupportedMediaType, utils.UnsupportedMediaTypeMessage).
+			WithDetails(map[string]any{
+				"content_type": contentType,
+				"supported":    config.contentTypes,
+			})
+	}
+
+	body := io.Reader(r.Body)
+	if config.maxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, config.maxBodySize)
+	}
+
+	dec := json.NewDecoder(body)
+	if config.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+
+	// The body must contain a single JSON value
+	if err := dec.Decode(&struct{}{}); !stderrors.Is(err, io.EOF) {
+		if err != nil {
+			return decodeError(err)
+		}
+		return errors.BadRequestErr(utils.MalformedBodyMessage).
+			WithDetails(map[string]any{"reason": "body must contain a single JSON value"})
+	}
+
+	return nil
+}
+
+// decodeError converts a JSON decoding error into an HTTPError
+func decodeError(err error) error {
+	if stderrors.Is(err, io.EOF) {
+		return errors.BadRequestErr(utils.EmptyBodyMessage).Wrap(err)
+	}
+
+	// encoding/json reports unknown fields as plain errors: json: unknown field "name"
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return errors.BadRequestErr(utils.InvalidFieldMessage).
+			WithDetails(map[string]any{
+				"field":  strings.Trim(field, `"`),
+				"reason": "unknown field",
+			}).
+			Wrap(err)
+	}
+
+	if httpErr, ok := errors.Translate(err); ok {
+		return httpErr
+	}
+
+	return errors.BadRequestErr(utils.MalformedBodyMessage).
+		WithDetails(map[string]any{"reason": err.Error()}).
+		Wrap(err)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+type bindTarget struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func newBindRequest(body, contentType string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestBind(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := newBindRequest(`{"name":"alice","age":30}`, "application/json; charset=utf-8")
+
+	result, ok := Bind[bindTarget](w, r)
+	if !ok {
+		t.Fatalf("Expected bind to succeed, got response %s", w.Body.String())
+	}
+
+	if result.Name != "alice" || result.Age != 30 {
+		t.Errorf("Expected decoded body, got %+v", result)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no response to be written, got %s", w.Body.String())
+	}
+}
+
+func TestBindFailures(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		opts           []BindOption
+		expectedStatus int
+		expectedField  string
+	}{
+		{"UnsupportedMediaType", `{"name":"alice"}`, "text/plain", nil, http.StatusUnsupportedMediaType, ""},
+		{"MissingContentType", `{"name":"alice"}`, "", nil, http.StatusUnsupportedMediaType, ""},
+		{"TooLarge", `{"name":"alice"}`, "application/json", []BindOption{WithMaxBodySize(4)}, http.StatusRequestEntityTooLarge, ""},
+		{"Empty", ``, "application/json", nil, http.StatusBadRequest, ""},
+		{"Malformed", `{"name":`, "application/json", nil, http.StatusBadRequest, ""},
+		{"WrongType", `{"age":"old"}`, "application/json", nil, http.StatusBadRequest, "age"},
+		{"UnknownField", `{"nickname":"al"}`, "application/json", []BindOption{DisallowUnknownFields()}, http.StatusBadRequest, "nickname"},
+		{"TrailingData", `{"name":"alice"} {"name":"bob"}`, "application/json", nil, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, ok := Bind[bindTarget](w, newBindRequest(tt.body, tt.contentType), tt.opts...)
+			if ok {
+				t.Fatalf("Expected bind to fail")
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response utils.NewResponse
+			if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.Success() {
+				t.Errorf("Expected success to be false")
+			}
+
+			if tt.expectedField != "" {
+				data, ok := response.Data().(map[string]any)
+				if !ok || data["field"] != tt.expectedField {
+					t.Errorf("Expected data with field '%s', got %v", tt.expectedField, response.Data())
+				}
+			}
+		})
+	}
+}
+
+type validatedTarget struct {
+	Username string `json:"username" validate:"required,min=3"`
+	Email    string `json:"email" validate:"required,email"`
+}
+
+func TestBindValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []BindOption
+		expectedStatus int
+	}{
+		{"Default", nil, http.StatusUnprocessableEntity},
+		{"BadRequest", []BindOption{WithValidationStatus(http.StatusBadRequest)}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newBindRequest(`{"username":"al","email":"nope"}`, "application/json")
+
+			if _, ok := Bind[validatedTarget](w, r, tt.opts...); ok {
+				t.Fatalf("Expected bind to fail validation")
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response utils.NewResponse
+			if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.Message() != utils.ValidationFailedMessage {
+				t.Errorf("Expected message '%s', got '%s'", utils.ValidationFailedMessage, response.Message())
+			}
+
+			data, _ := response.Data().(map[string]any)
+			errs, ok := data["errors"].([]any)
+			if !ok || len(errs) != 2 {
+				t.Fatalf("Expected 2 field errors, got %v", response.Data())
+			}
+
+			first, _ := errs[0].(map[string]any)
+			if first["field"] != "username" || first["rule"] != "min" || first["pointer"] != "/username" {
+				t.Errorf("Expected username min error, got %v", first)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// HandlerFunc is an HTTP handler that returns an error instead of writing error responses itself
+// A returned error is rendered with the matching gecho error helper
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ServeHTTP calls fn and renders a returned error, logging it with the logger of the request context
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveWithErrors(w, r, fn, utils.LoggerFrom(r.Context()))
+}
+
+// HandleErrors adapts fn to an http.Handler that renders returned errors and logs their cause
+// A nil logger uses the logger of the request context, see utils.LoggerFrom
+// Example: mux.Handle("/users/", gecho.Handlers.HandleErrors(getUser, logger))
+func (h *Handlers) HandleErrors(fn HandlerFunc, logger *utils.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if logger == nil {
+			serveWithErrors(w, r, fn, utils.LoggerFrom(r.Context()))
+			return
+		}
+		serveWithErrors(w, r, fn, logger.WithContext(r.Context()))
+	})
+}
+
+// serveWithErrors calls fn and writes the error response for a returned error
+// When fn has already written a response the error is only logged, as a second response cannot be sent
+func serveWithErrors(w http.ResponseWriter, r *http.Request, fn HandlerFunc, logger *utils.Logger) {
+	tracked := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	err := fn(tracked, r)
+	if err == nil {
+		return
+	}
+
+	httpErr := errors.AsHTTPError(err)
+	fields := []any{
+		utils.Field("method", r.Method),
+		utils.Field("path", r.URL.Path),
+		utils.Field("status", httpErr.Status),
+		utils.Field("error", err.Error()),
+	}
+
+	switch {
+	case tracked.HeaderWritten():
+		logger.Error(append([]any{"Request failed after the response was written", utils.Field("sent_status", tracked.statusCode)}, fields...)...)
+		return
+	case httpErr.Status == errors.StatusClientClosedRequest:
+		logger.Info(append([]any{"Client closed request"}, fields...)...)
+	case httpErr.Status < 500:
+		logger.Debug(append([]any{"Request rejected"}, fields...)...)
+	}
+
+	// Server errors are logged by the response, under the reference ID it sends
+	errors.Respond(w, err, utils.WithRequest(r), utils.WithErrorLogger(logger), utils.Send())
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestHandleErrors(t *testing.T) {
+	t.Run("NoError", func(t *testing.T) {
+		fn := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			utils.NewOK(w, utils.Send())
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		fn.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("HTTPError", func(t *testing.T) {
+		fn := func(w http.ResponseWriter, r *http.Request) error {
+			return errors.NotFoundErr("user")
+		}
+
+		w := httptest.NewRecorder()
+		NewHandlers().HandleErrors(fn, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+
+		var response utils.NewResponse
+		if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if response.Message() != "user not found" {
+			t.Errorf("Expected message 'user not found', got '%s'", response.Message())
+		}
+	})
+
+	t.Run("PlainErrorLoggedAndHidden", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := utils.NewLogger(utils.NewConfig(
+			utils.WithOutput(&buf),
+			utils.WithErrorOutput(&buf),
+			utils.WithLogFormat(utils.FormatJSON),
+		))
+
+		fn := func(w http.ResponseWriter, r *http.Request) error {
+			return stderrors.New("pq: password authentication failed")
+		}
+
+		w := httptest.NewRecorder()
+		NewHandlers().HandleErrors(fn, logger).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+
+		if strings.Contains(w.Body.String(), "password") {
+			t.Errorf("Expected internal error message to be hidden, got %s", w.Body.String())
+		}
+
+		if !strings.Contains(buf.String(), "pq: password authentication failed") {
+			t.Errorf("Expected cause to be logged, got %s", buf.String())
+		}
+	})
+
+	t.Run("ErrorAfterResponse", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := utils.NewLogger(utils.NewConfig(
+			utils.WithOutput(&buf),
+			utils.WithErrorOutput(&buf),
+			utils.WithLogFormat(utils.FormatJSON),
+		))
+
+		fn := func(w http.ResponseWriter, r *http.Request) error {
+			utils.NewOK(w, utils.WithData("partial"), utils.Send())
+			return stderrors.New("audit log write failed")
+		}
+
+		w := httptest.NewRecorder()
+		NewHandlers().HandleErrors(fn, logger).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected the first response to stand, got %d", w.Code)
+		}
+		if strings.Count(w.Body.String(), `"status"`) != 1 {
+			t.Errorf("Expected a single envelope, got %s", w.Body.String())
+		}
+		if !strings.Contains(buf.String(), "Request failed after the response was written") || !strings.Contains(buf.String(), "audit log write failed") {
+			t.Errorf("Expected the late error to be logged, got %s", buf.String())
+		}
+	})
+
+	t.Run("RespondAfterWrite", func(t *testing.T) {
+		var sendErr error
+		fn := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			w.WriteHeader(http.StatusAccepted)
+			sendErr = utils.NewOK(w).Send()
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		fn.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if !stderrors.Is(sendErr, utils.ErrHeadersCommitted) {
+			t.Errorf("Expected ErrHeadersCommitted, got %v", sendErr)
+		}
+		if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+			t.Errorf("Expected only the 202, got %d with %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("ContextLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := utils.NewLogger(utils.NewConfig(
+			utils.WithOutput(&buf),
+			utils.WithErrorOutput(&buf),
+			utils.WithLogFormat(utils.FormatJSON),
+		))
+
+		fn := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			utils.LoggerFrom(r.Context()).Info("Loading report")
+			return stderrors.New("report store offline")
+		})
+
+		w := httptest.NewRecorder()
+		handler := NewHandlers().HandleLogging(NewHandlers().HandleRequestID(fn), logger)
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports", nil))
+
+		id := w.Header().Get(utils.RequestIDHeader)
+		for _, expected := range []string{"Loading report", "report store offline", `"request_id":"` + id + `"`} {
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("Expected %q to be logged through the middleware logger, got %s", expected, buf.String())
+			}
+		}
+	})
+}
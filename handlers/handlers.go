@@ -29,50 +29,70 @@ func (h *Handlers) CreateLoggingMiddleware(logger *utils.Logger) func(http.Handl
 	}
 }
 
+// HandleLogging logs every request with its status and duration
+// The logger is stored in the request context, handlers get it bound to their context with utils.LoggerFrom
 func (h *Handlers) HandleLogging(next http.Handler, logger *utils.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		// Create a response writer wrapper to capture status code
 		wrapper := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-		next.ServeHTTP(wrapper, r)
+		next.ServeHTTP(wrapper, r.WithContext(utils.WithLogger(r.Context(), logger)))
 
 		duration := time.Since(start)
-		if wrapper.statusCode >= 500 {
-			logger.Error(
-				utils.Field("method", r.Method),
-				utils.Field("path", r.URL.Path),
-				utils.Field("status", wrapper.statusCode),
-				utils.Field("duration", duration),
-				utils.Field("remote_addr", r.RemoteAddr),
-			)
+		fields := []any{
+			utils.Field("method", r.Method),
+			utils.Field("path", r.URL.Path),
+			utils.Field("status", wrapper.statusCode),
+			utils.Field("duration", duration),
+			utils.Field("remote_addr", r.RemoteAddr),
+		}
+
+		// The request ID middleware may run inside this one, then the ID is only found in the response headers
+		if utils.RequestIDFrom(r.Context()) == "" {
+			if id := w.Header().Get(utils.RequestIDHeader); id != "" {
+				fields = append(fields, utils.Field(utils.RequestIDField, id))
+			}
+		}
 
+		requestLogger := logger.WithContext(r.Context())
+		if wrapper.statusCode >= 500 {
+			requestLogger.Error(fields...)
 		} else if wrapper.statusCode >= 400 {
-			logger.Warn(
-				utils.Field("method", r.Method),
-				utils.Field("path", r.URL.Path),
-				utils.Field("status", wrapper.statusCode),
-				utils.Field("duration", duration),
-				utils.Field("remote_addr", r.RemoteAddr),
-			)
+			requestLogger.Warn(fields...)
 		} else {
-			logger.Info(
-				utils.Field("method", r.Method),
-				utils.Field("path", r.URL.Path),
-				utils.Field("status", wrapper.statusCode),
-				utils.Field("duration", duration),
-				utils.Field("remote_addr", r.RemoteAddr),
-			)
+			requestLogger.Info(fields...)
 		}
 	})
 }
 
-// responseWriter is a wrapper to capture the status code
+// responseWriter is a wrapper to capture the status code and whether the headers were written
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	wroteHeader bool
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		// Informational responses are followed by the final status
+		rw.wroteHeader = code < 100 || code >= 200 || code == http.StatusSwitchingProtocols
+	}
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Write writes the body, an implicit 200 OK is sent when no status was written
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// HeaderWritten reports whether the status line has been written, see utils.HeaderTracker
+func (rw *responseWriter) HeaderWritten() bool {
+	return rw.wroteHeader
+}
+
+// Unwrap returns the underlying writer, so http.ResponseController can reach its Flush and deadlines
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -454,3 +454,21 @@ func TestHandleLogging(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleLoggingFlush(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("chunk"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Expected the wrapped writer to flush, got %v", err)
+		}
+	})
+
+	loggingHandler := NewHandlers().HandleLogging(testHandler, utils.NewDefaultLogger())
+
+	w := httptest.NewRecorder()
+	loggingHandler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if !w.Flushed {
+		t.Error("Expected the underlying writer to be flushed")
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// HandleHEAD serves HEAD requests with a GET handler
+// The handler sees a GET request, its body is discarded and only its length is sent as Content-Length
+// Example: mux.Handle("/users", gecho.HandleHEAD(http.HandlerFunc(listUsers)))
+func (h *Handlers) HandleHEAD(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		get := r.Clone(r.Context())
+		get.Method = http.MethodGet
+
+		hw := &headWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(hw, get)
+		hw.commit(true)
+	})
+}
+
+// headWriter discards the body of a response, counting its length for Content-Length
+// The status is held back until the handler returns, unless the handler flushes
+type headWriter struct {
+	http.ResponseWriter
+	status      int
+	written     int64
+	wroteHeader bool
+	committed   bool
+}
+
+// WriteHeader records the status, it is sent once the length of the body is known
+func (hw *headWriter) WriteHeader(code int) {
+	if hw.committed {
+		return
+	}
+	// Informational responses are sent right away, the final status follows
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		hw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if !hw.wroteHeader {
+		hw.status = code
+		hw.wroteHeader = true
+	}
+}
+
+// Write discards the body, counting its length
+func (hw *headWriter) Write(b []byte) (int, error) {
+	hw.wroteHeader = true
+	hw.written += int64(len(b))
+	return len(b), nil
+}
+
+// HeaderWritten reports whether the handler has written its status, see utils.HeaderTracker
+func (hw *headWriter) HeaderWritten() bool {
+	return hw.wroteHeader || hw.committed
+}
+
+// Flush sends the status without a length, as a flushing handler streams a body of unknown size
+func (hw *headWriter) Flush() {
+	hw.commit(false)
+	http.NewResponseController(hw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer, so http.ResponseController can reach its deadlines
+func (hw *headWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
+
+// commit sends the held back status, with the counted length when known is true
+func (hw *headWriter) commit(known bool) {
+	if hw.committed {
+		return
+	}
+	hw.committed = true
+
+	header := hw.Header()
+	if known && header.Get("Content-Length") == "" && utils.StatusAllowsBody(hw.status) {
+		header.Set("Content-Length", strconv.FormatInt(hw.written, 10))
+	}
+	header.Del("Trailer")
+	hw.ResponseWriter.WriteHeader(hw.status)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestHandleHEAD(t *testing.T) {
+	var seenMethod string
+	getHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenMethod = r.Method
+		if err := NewHandlers().HandleMethod(w, r, http.MethodGet); err != nil {
+			return
+		}
+		utils.NewOK(w, utils.WithRequest(r), utils.WithData(map[string]string{"name": "Alice"}), utils.Send())
+	})
+
+	handler := NewHandlers().HandleHEAD(getHandler)
+
+	get := httptest.NewRecorder()
+	handler.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	head := httptest.NewRecorder()
+	handler.ServeHTTP(head, httptest.NewRequest(http.MethodHead, "/users/1", nil))
+
+	if seenMethod != http.MethodGet {
+		t.Errorf("Expected the handler to see GET, got %s", seenMethod)
+	}
+	if head.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", head.Code)
+	}
+	if head.Body.Len() != 0 {
+		t.Errorf("Expected no body, got %s", head.Body.String())
+	}
+	if head.Header().Get("Content-Type") != utils.MediaTypeJSON {
+		t.Errorf("Expected Content-Type %s, got %s", utils.MediaTypeJSON, head.Header().Get("Content-Type"))
+	}
+
+	if head.Header().Get("Content-Length") == "" {
+		t.Errorf("Expected a Content-Length, GET body is %d bytes", get.Body.Len())
+	}
+}
+
+func TestHandleHEADStatuses(t *testing.T) {
+	t.Run("ContentLength", func(t *testing.T) {
+		handler := NewHandlers().HandleHEAD(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello"))
+			w.Write([]byte(" world"))
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/", nil))
+
+		if w.Header().Get("Content-Length") != "11" || w.Body.Len() != 0 {
+			t.Errorf("Expected Content-Length 11 and no body, got %q and %q", w.Header().Get("Content-Length"), w.Body.String())
+		}
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		handler := NewHandlers().HandleHEAD(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			utils.NewErr(w, utils.WithStatus(http.StatusNotFound), utils.Send())
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/missing", nil))
+
+		if w.Code != http.StatusNotFound || w.Body.Len() != 0 {
+			t.Errorf("Expected an empty 404, got %d with %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("NoContent", func(t *testing.T) {
+		handler := NewHandlers().HandleHEAD(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/", nil))
+
+		if w.Code != http.StatusNoContent || w.Header().Get("Content-Length") != "" {
+			t.Errorf("Expected 204 without Content-Length, got %d with %q", w.Code, w.Header().Get("Content-Length"))
+		}
+	})
+
+	t.Run("OtherMethodsPassThrough", func(t *testing.T) {
+		handler := NewHandlers().HandleHEAD(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Method))
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+		if w.Body.String() != http.MethodPost {
+			t.Errorf("Expected the POST body, got %q", w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/success"
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// logLevels is the body of the log level endpoint
+type logLevels struct {
+	Level     utils.Level            `json:"level"`
+	Overrides map[string]utils.Level `json:"overrides"`
+}
+
+// logLevelsUpdate is the request body of a PUT, a null override removes it
+type logLevelsUpdate struct {
+	Level     *utils.Level            `json:"level"`
+	Overrides map[string]*utils.Level `json:"overrides"`
+}
+
+// HandleLogLevel returns an admin endpoint reading and changing the levels of a LevelVar at runtime
+// GET returns {"level":"info","overrides":{"db":"debug"}} in a gecho envelope
+// PUT takes the same body, changing only the level and overrides it contains, a null override removes it
+// The endpoint changes the logging of the whole program, mount it behind authentication
+// Example: mux.Handle("/admin/log-level", gecho.Handlers.HandleLogLevel(logger.LevelVar()))
+func (h *Handlers) HandleLogLevel(levels *utils.LevelVar) http.Handler {
+	return HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut:
+			update, ok := Bind[logLevelsUpdate](w, r)
+			if !ok {
+				return nil
+			}
+			// Nothing is changed when a name cannot match a logger
+			for name := range update.Overrides {
+				if err := utils.ValidateLevelPattern(name); err != nil {
+					return errors.BadRequestErr(utils.InvalidFieldMessage).
+						WithDetails(map[string]any{
+							"field":  "overrides." + name,
+							"reason": `must be a logger name, optionally ending with ".*"`,
+						}).
+						Wrap(err)
+				}
+			}
+			if update.Level != nil {
+				levels.Set(*update.Level)
+			}
+			for name, level := range update.Overrides {
+				if level == nil {
+					levels.RemoveOverride(name)
+				} else {
+					levels.SetOverride(name, *level)
+				}
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			return errors.MethodNotAllowedErr(r.Method)
+		}
+
+		current := logLevels{Level: levels.Level(), Overrides: levels.Overrides()}
+		success.Success(w, utils.WithData(current), utils.WithRequest(r), utils.Send())
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestHandleLogLevel(t *testing.T) {
+	levels := utils.NewLevelVar(utils.LevelInfo)
+	handler := NewHandlers().HandleLogLevel(levels)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":{"level":"info","overrides":{}}`) {
+			t.Errorf("Expected the current level in an envelope, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		w := put(`{"level":"warn","overrides":{"db":"debug"}}`)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":{"level":"warn","overrides":{"db":"debug"}}`) {
+			t.Errorf("Expected the new levels, got %d %s", w.Code, w.Body.String())
+		}
+		if levels.Level() != utils.LevelWarn || levels.LevelFor("db") != utils.LevelDebug {
+			t.Errorf("Expected the levels to change, got %v and %v", levels.Level(), levels.LevelFor("db"))
+		}
+
+		put(`{"overrides":{"db":null}}`)
+		if levels.Level() != utils.LevelWarn || len(levels.Overrides()) != 0 {
+			t.Errorf("Expected only the override to be removed, got %v %v", levels.Level(), levels.Overrides())
+		}
+	})
+
+	t.Run("InvalidLevel", func(t *testing.T) {
+		w := put(`{"level":"verbose"}`)
+		if w.Code != http.StatusBadRequest || levels.Level() != utils.LevelWarn {
+			t.Errorf("Expected 400 and an unchanged level, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		for _, name := range []string{"*", "a*b", "foo*", "", ".*"} {
+			w := put(`{"level":"error","overrides":{"` + name + `":"debug"}}`)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"overrides.`+name+`"`) {
+				t.Errorf("Expected 400 for %q, got %d %s", name, w.Code, w.Body.String())
+			}
+		}
+		if levels.Level() != utils.LevelWarn || len(levels.Overrides()) != 0 {
+			t.Errorf("Expected unchanged levels, got %v %v", levels.Level(), levels.Overrides())
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/log-level", nil))
+
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD, PUT" {
+			t.Errorf("Expected 405 with the allowed methods, got %d %q", w.Code, w.Header().Get("Allow"))
+		}
+	})
+}
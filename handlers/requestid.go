@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// RequestIDOption is a function that configures HandleRequestID
+type RequestIDOption func(*requestIDConfig)
+
+// requestIDConfig holds the configuration of HandleRequestID
+type requestIDConfig struct {
+	header        string
+	generate      utils.IDGenerator
+	trustIncoming bool
+}
+
+// WithRequestIDHeader sets the header the request ID is read from and echoed in, defaults to X-Request-ID
+func WithRequestIDHeader(header string) RequestIDOption {
+	return func(rc *requestIDConfig) {
+		rc.header = header
+	}
+}
+
+// WithIDGenerator sets the generator of new request IDs, defaults to utils.NewUUIDv7
+func WithIDGenerator(generate utils.IDGenerator) RequestIDOption {
+	return func(rc *requestIDConfig) {
+		rc.generate = generate
+	}
+}
+
+// WithTrustIncoming sets whether valid IDs sent by the client are reused, defaults to true
+// Disable it for public endpoints where clients should not choose the IDs found in your logs
+func WithTrustIncoming(trust bool) RequestIDOption {
+	return func(rc *requestIDConfig) {
+		rc.trustIncoming = trust
+	}
+}
+
+// HandleRequestID gives every request an ID, stored in its context and echoed in the X-Request-ID header
+// A valid incoming ID is reused, otherwise a new one is generated. The correlation ID is taken from
+// X-Correlation-ID and defaults to the request ID, so it can follow a request across services
+// Responses include the ID in their request_id field and loggers add it when given the request context
+// Example: mux.Handle("/", gecho.Handlers.HandleRequestID(router))
+func (h *Handlers) HandleRequestID(next http.Handler, opts ...RequestIDOption) http.Handler {
+	config := requestIDConfig{
+		header:        utils.RequestIDHeader,
+		generate:      utils.NewUUIDv7,
+		trustIncoming: true,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := ""
+		if config.trustIncoming {
+			if incoming := r.Header.Get(config.header); utils.ValidRequestID(incoming) {
+				id = incoming
+			}
+		}
+		if id == "" {
+			id = config.generate()
+		}
+
+		correlationID := id
+		if config.trustIncoming {
+			if incoming := r.Header.Get(utils.CorrelationIDHeader); utils.ValidRequestID(incoming) {
+				correlationID = incoming
+			}
+		}
+
+		ctx := utils.ContextWithRequestID(r.Context(), id)
+		ctx = utils.ContextWithCorrelationID(ctx, correlationID)
+
+		w.Header().Set(config.header, id)
+		w.Header().Set(utils.CorrelationIDHeader, correlationID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CreateRequestIDMiddleware returns HandleRequestID as a middleware function
+func (h *Handlers) CreateRequestIDMiddleware(opts ...RequestIDOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return h.HandleRequestID(next, opts...)
+	}
+}
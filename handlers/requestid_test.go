@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestHandleRequestID(t *testing.T) {
+	var seen, seenCorrelation string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.RequestIDFrom(r.Context())
+		seenCorrelation = utils.CorrelationIDFrom(r.Context())
+		utils.NewOKFor(w, r, utils.Send())
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandlers().HandleRequestID(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if seen == "" || w.Header().Get(utils.RequestIDHeader) != seen {
+			t.Errorf("Expected the generated ID %q to be echoed, got %q", seen, w.Header().Get(utils.RequestIDHeader))
+		}
+		if seenCorrelation != seen {
+			t.Errorf("Expected the correlation ID to default to the request ID, got %q", seenCorrelation)
+		}
+		if !strings.Contains(w.Body.String(), `"request_id":"`+seen+`"`) {
+			t.Errorf("Expected the ID in the envelope, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Incoming", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(utils.RequestIDHeader, "upstream-1")
+		r.Header.Set(utils.CorrelationIDHeader, "journey-9")
+
+		w := httptest.NewRecorder()
+		NewHandlers().HandleRequestID(next).ServeHTTP(w, r)
+
+		if seen != "upstream-1" || seenCorrelation != "journey-9" {
+			t.Errorf("Expected the incoming IDs, got %q and %q", seen, seenCorrelation)
+		}
+		if w.Header().Get(utils.CorrelationIDHeader) != "journey-9" {
+			t.Errorf("Expected the correlation ID to be echoed, got %q", w.Header().Get(utils.CorrelationIDHeader))
+		}
+	})
+
+	t.Run("InvalidIncoming", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(utils.RequestIDHeader, "bad id<script>")
+
+		NewHandlers().HandleRequestID(next).ServeHTTP(httptest.NewRecorder(), r)
+		if seen == "bad id<script>" || seen == "" {
+			t.Errorf("Expected a generated ID, got %q", seen)
+		}
+	})
+
+	t.Run("Untrusted", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(utils.RequestIDHeader, "upstream-1")
+
+		handler := NewHandlers().HandleRequestID(next, WithTrustIncoming(false), WithIDGenerator(func() string { return "fixed" }))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		if seen != "fixed" {
+			t.Errorf("Expected the generated ID, got %q", seen)
+		}
+	})
+
+	t.Run("CustomHeader", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Trace-ID", "trace-5")
+
+		w := httptest.NewRecorder()
+		NewHandlers().CreateRequestIDMiddleware(WithRequestIDHeader("X-Trace-ID"))(next).ServeHTTP(w, r)
+		if seen != "trace-5" || w.Header().Get("X-Trace-ID") != "trace-5" {
+			t.Errorf("Expected the custom header to be used, got %q", seen)
+		}
+	})
+}
+
+func TestHandleLoggingRequestID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, tt := range []struct {
+		name  string
+		outer bool
+	}{
+		{"LoggingOutside", true},
+		{"LoggingInside", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := utils.NewLogger(utils.NewConfig(utils.WithOutput(&buf), utils.WithLogFormat(utils.FormatJSON)))
+			h := NewHandlers()
+			ids := h.CreateRequestIDMiddleware(WithIDGenerator(func() string { return "req-7" }))
+			logging := h.CreateLoggingMiddleware(logger)
+
+			var handler http.Handler
+			if tt.outer {
+				handler = logging(ids(next))
+			} else {
+				handler = ids(logging(next))
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if !strings.Contains(buf.String(), `"request_id":"req-7"`) {
+				t.Errorf("Expected the request ID to be logged, got %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
+package gecho
+
+import (
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/redirect"
+	"github.com/MonkyMars/gecho/success"
+)
+
+// Exported Success Functions
+var Success = success.Success
+var Created = success.Created
+var Accepted = success.Accepted
+var NonAuthoritativeInfo = success.NonAuthoritativeInfo
+var NoContent = success.NoContent
+var ResetContent = success.ResetContent
+var PartialContent = success.PartialContent
+var MultiStatus = success.MultiStatus
+var AlreadyReported = success.AlreadyReported
+
+// Exported Redirect Functions
+var MovedPermanently = redirect.MovedPermanently
+var Found = redirect.Found
+var SeeOther = redirect.SeeOther
+var TemporaryRedirect = redirect.TemporaryRedirect
+var PermanentRedirect = redirect.PermanentRedirect
+
+// Exported Client Error Functions
+var BadRequest = errors.BadRequest
+var Unauthorized = errors.Unauthorized
+var PaymentRequired = errors.PaymentRequired
+var Forbidden = errors.Forbidden
+var NotFound = errors.NotFound
+var MethodNotAllowed = errors.MethodNotAllowed
+var NotAcceptable = errors.NotAcceptable
+var ProxyAuthRequired = errors.ProxyAuthRequired
+var RequestTimeout = errors.RequestTimeout
+var Conflict = errors.Conflict
+var Gone = errors.Gone
+var LengthRequired = errors.LengthRequired
+var PreconditionFailed = errors.PreconditionFailed
+var RequestEntityTooLarge = errors.RequestEntityTooLarge
+var RequestURITooLong = errors.RequestURITooLong
+var UnsupportedMediaType = errors.UnsupportedMediaType
+var RequestedRangeNotSatisfiable = errors.RequestedRangeNotSatisfiable
+var ExpectationFailed = errors.ExpectationFailed
+var Teapot = errors.Teapot
+var MisdirectedRequest = errors.MisdirectedRequest
+var UnprocessableEntity = errors.UnprocessableEntity
+var Locked = errors.Locked
+var FailedDependency = errors.FailedDependency
+var TooEarly = errors.TooEarly
+var UpgradeRequired = errors.UpgradeRequired
+var PreconditionRequired = errors.PreconditionRequired
+var TooManyRequests = errors.TooManyRequests
+var RequestHeaderFieldsTooLarge = errors.RequestHeaderFieldsTooLarge
+var UnavailableForLegalReasons = errors.UnavailableForLegalReasons
+
+// Exported Server Error Functions
+var InternalServerError = errors.InternalServerError
+var NotImplemented = errors.NotImplemented
+var BadGateway = errors.BadGateway
+var ServiceUnavailable = errors.ServiceUnavailable
+var GatewayTimeout = errors.GatewayTimeout
+var HTTPVersionNotSupported = errors.HTTPVersionNotSupported
+var VariantAlsoNegotiates = errors.VariantAlsoNegotiates
+var InsufficientStorage = errors.InsufficientStorage
+var LoopDetected = errors.LoopDetected
+var NotExtended = errors.NotExtended
+var NetworkAuthenticationRequired = errors.NetworkAuthenticationRequired
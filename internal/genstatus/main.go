@@ -0,0 +1,303 @@
+// Command genstatus generates the status helpers of gecho from a single table
+//
+// It writes the message constants in utils, the helpers in success, redirect and errors,
+// and their re-exports in the root package. Run it from the module root with go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// status describes a single status code and its helper
+type status struct {
+	Code    int    // HTTP status code
+	Name    string // Name of the helper, the message constant is Name + "Message"
+	Const   string // Name of the net/http constant, defaults to "Status" + Name
+	Message string // Default message of the response
+	Example string // Options shown in the doc comment example, or the location of a redirect
+}
+
+// statuses is the table every generated file is built from
+var statuses = []status{
+	{Code: 200, Name: "Success", Const: "StatusOK", Message: "Success", Example: "gecho.WithData(userData)"},
+	{Code: 201, Name: "Created", Message: "Resource Created", Example: "gecho.WithData(newResource)"},
+	{Code: 202, Name: "Accepted", Message: "Accepted", Example: `gecho.WithMessage("Request accepted for processing")`},
+	{Code: 203, Name: "NonAuthoritativeInfo", Message: "Non-authoritative information"},
+	{Code: 204, Name: "NoContent", Message: "No Content"},
+	{Code: 205, Name: "ResetContent", Message: "Reset content"},
+	{Code: 206, Name: "PartialContent", Message: "Partial content", Example: "gecho.WithData(chunk)"},
+	{Code: 207, Name: "MultiStatus", Message: "Multi-status", Example: "gecho.WithData(results)"},
+	{Code: 208, Name: "AlreadyReported", Message: "Already reported"},
+
+	{Code: 301, Name: "MovedPermanently", Message: "Moved permanently", Example: `"/new-path"`},
+	{Code: 302, Name: "Found", Message: "Found", Example: `"/login"`},
+	{Code: 303, Name: "SeeOther", Message: "See other", Example: `"/orders/42"`},
+	{Code: 307, Name: "TemporaryRedirect", Message: "Temporary redirect", Example: `"/maintenance"`},
+	{Code: 308, Name: "PermanentRedirect", Message: "Permanent redirect", Example: `"/v2/users"`},
+
+	{Code: 400, Name: "BadRequest", Message: "Bad request", Example: "gecho.WithData(validationErrors)"},
+	{Code: 401, Name: "Unauthorized", Message: "Unauthorized"},
+	{Code: 402, Name: "PaymentRequired", Message: "Payment required"},
+	{Code: 403, Name: "Forbidden", Message: "Forbidden", Example: `gecho.WithMessage("Access denied")`},
+	{Code: 404, Name: "NotFound", Message: "Resource not found"},
+	{Code: 405, Name: "MethodNotAllowed", Message: "Method not allowed"},
+	{Code: 406, Name: "NotAcceptable", Message: "Not acceptable"},
+	{Code: 407, Name: "ProxyAuthRequired", Message: "Proxy authentication required"},
+	{Code: 408, Name: "RequestTimeout", Message: "Request timeout"},
+	{Code: 409, Name: "Conflict", Message: "Conflict", Example: `gecho.WithMessage("Resource already exists")`},
+	{Code: 410, Name: "Gone", Message: "Resource gone"},
+	{Code: 411, Name: "LengthRequired", Message: "Length required"},
+	{Code: 412, Name: "PreconditionFailed", Message: "Precondition failed"},
+	{Code: 413, Name: "RequestEntityTooLarge", Message: "Request entity too large"},
+	{Code: 414, Name: "RequestURITooLong", Message: "Request URI too long"},
+	{Code: 415, Name: "UnsupportedMediaType", Message: "Unsupported media type"},
+	{Code: 416, Name: "RequestedRangeNotSatisfiable", Message: "Requested range not satisfiable"},
+	{Code: 417, Name: "ExpectationFailed", Message: "Expectation failed"},
+	{Code: 418, Name: "Teapot", Message: "I'm a teapot"},
+	{Code: 421, Name: "MisdirectedRequest", Message: "Misdirected request"},
+	{Code: 422, Name: "UnprocessableEntity", Message: "Unprocessable entity", Example: "gecho.WithData(validationErrors)"},
+	{Code: 423, Name: "Locked", Message: "Resource locked"},
+	{Code: 424, Name: "FailedDependency", Message: "Failed dependency"},
+	{Code: 425, Name: "TooEarly", Message: "Too early"},
+	{Code: 426, Name: "UpgradeRequired", Message: "Upgrade required"},
+	{Code: 428, Name: "PreconditionRequired", Message: "Precondition required"},
+	{Code: 429, Name: "TooManyRequests", Message: "Too many requests", Example: `gecho.WithMessage("Rate limit exceeded")`},
+	{Code: 431, Name: "RequestHeaderFieldsTooLarge", Message: "Request header fields too large"},
+	{Code: 451, Name: "UnavailableForLegalReasons", Message: "Unavailable for legal reasons"},
+
+	{Code: 500, Name: "InternalServerError", Message: "Internal server error"},
+	{Code: 501, Name: "NotImplemented", Message: "Not implemented"},
+	{Code: 502, Name: "BadGateway", Message: "Bad gateway"},
+	{Code: 503, Name: "ServiceUnavailable", Message: "Service unavailable", Example: `gecho.WithMessage("Maintenance mode")`},
+	{Code: 504, Name: "GatewayTimeout", Message: "Gateway timeout"},
+	{Code: 505, Name: "HTTPVersionNotSupported", Message: "HTTP version not supported"},
+	{Code: 506, Name: "VariantAlsoNegotiates", Message: "Variant also negotiates"},
+	{Code: 507, Name: "InsufficientStorage", Message: "Insufficient storage"},
+	{Code: 508, Name: "LoopDetected", Message: "Loop detected"},
+	{Code: 510, Name: "NotExtended", Message: "Not extended"},
+	{Code: 511, Name: "NetworkAuthenticationRequired", Message: "Network authentication required"},
+}
+
+// header is written at the top of every generated file
+const header = "// Code generated by go run ./internal/genstatus; DO NOT EDIT.\n\n"
+
+// files maps each generated file, relative to the module root, to its template
+var files = map[string]string{
+	"utils/messages.go": `package utils
+
+// Default messages of the status helpers
+{{range .All}}const {{.Name}}Message = {{printf "%q" .Message}}
+{{end}}`,
+
+	"success/common.go": `package success
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+{{range .Success}}
+// {{.Name}} sends a {{.Code}} {{.Text}} response with optional configuration
+// Example: success.{{.Name}}(w, {{with .Example}}{{.}}, {{end}}gecho.Send())
+func {{.Name}}(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.{{.HTTPConst}}),
+		utils.WithMessage(utils.{{.Name}}Message),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+{{end}}`,
+
+	"redirect/redirect.go": `package redirect
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+{{range .Redirect}}
+// {{.Name}} sends a {{.Code}} {{.Text}} response redirecting to location with optional configuration
+// Example: redirect.{{.Name}}(w, {{.Example}}, gecho.Send())
+func {{.Name}}(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.{{.HTTPConst}}, utils.{{.Name}}Message, location, opts)
+}
+{{end}}`,
+
+	"errors/client.go": `package errors
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+{{range .Client}}
+// {{.Name}} sends a {{.Code}} {{.Text}} response with optional configuration
+// Example: errors.{{.Name}}(w, {{with .Example}}{{.}}, {{end}}gecho.Send())
+func {{.Name}}(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.{{.HTTPConst}}),
+		utils.WithMessage(utils.{{.Name}}Message),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+{{end}}`,
+
+	"errors/server.go": `package errors
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+{{range .Server}}
+// {{.Name}} sends a {{.Code}} {{.Text}} response with optional configuration
+// Example: errors.{{.Name}}(w, {{with .Example}}{{.}}, {{end}}gecho.Send())
+func {{.Name}}(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.{{.HTTPConst}}),
+		utils.WithMessage(utils.{{.Name}}Message),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewErr(w, allOpts...)
+}
+{{end}}`,
+
+	"errors/helpers.go": `package errors
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// statusHelpers maps status codes to the helper that renders them
+var statusHelpers = map[int]func(http.ResponseWriter, ...utils.ResponseOption) *utils.Response{
+{{range .Client}}	http.{{.HTTPConst}}: {{.Name}},
+{{end}}{{range .Server}}	http.{{.HTTPConst}}: {{.Name}},
+{{end}}}
+`,
+
+	"helpers.go": `package gecho
+
+import (
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/redirect"
+	"github.com/MonkyMars/gecho/success"
+)
+
+// Exported Success Functions
+{{range .Success}}var {{.Name}} = success.{{.Name}}
+{{end}}
+// Exported Redirect Functions
+{{range .Redirect}}var {{.Name}} = redirect.{{.Name}}
+{{end}}
+// Exported Client Error Functions
+{{range .Client}}var {{.Name}} = errors.{{.Name}}
+{{end}}
+// Exported Server Error Functions
+{{range .Server}}var {{.Name}} = errors.{{.Name}}
+{{end}}`,
+}
+
+// entry is a status as seen by the templates
+type entry struct {
+	status
+	Text      string // Status text from net/http
+	HTTPConst string // Name of the net/http constant
+}
+
+// data groups the table by status class for the templates
+type data struct {
+	All      []entry
+	Success  []entry
+	Redirect []entry
+	Client   []entry
+	Server   []entry
+}
+
+// group validates the table and splits it by status class
+func group() (data, error) {
+	var d data
+	seen := make(map[string]bool)
+	for _, s := range statuses {
+		text := http.StatusText(s.Code)
+		if text == "" {
+			return d, fmt.Errorf("unknown status code %d", s.Code)
+		}
+		if seen[s.Name] {
+			return d, fmt.Errorf("duplicate helper name %s", s.Name)
+		}
+		seen[s.Name] = true
+
+		e := entry{status: s, Text: text, HTTPConst: s.Const}
+		if e.HTTPConst == "" {
+			e.HTTPConst = "Status" + s.Name
+		}
+
+		d.All = append(d.All, e)
+		switch s.Code / 100 {
+		case 2:
+			d.Success = append(d.Success, e)
+		case 3:
+			d.Redirect = append(d.Redirect, e)
+		case 4:
+			d.Client = append(d.Client, e)
+		case 5:
+			d.Server = append(d.Server, e)
+		default:
+			return d, fmt.Errorf("status code %d has no helper class", s.Code)
+		}
+	}
+	return d, nil
+}
+
+// generate renders every file, returning their formatted contents by path
+func generate() (map[string][]byte, error) {
+	d, err := group()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte, len(files))
+	for path, text := range files {
+		tmpl, err := template.New(path).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString(header)
+		if err := tmpl.Execute(&buf, d); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		out[path] = src
+	}
+	return out, nil
+}
+
+func main() {
+	out, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for path, src := range out {
+		if err := os.WriteFile(filepath.FromSlash(path), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
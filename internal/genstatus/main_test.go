@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	out, err := generate()
+	if err != nil {
+		t.Fatalf("Expected no error on generate(), got %v", err)
+	}
+
+	for path, expected := range out {
+		actual, err := os.ReadFile(filepath.Join("..", "..", filepath.FromSlash(path)))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", path, err)
+			continue
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s is out of date, run go generate", path)
+		}
+	}
+}
+
+func TestStatusTable(t *testing.T) {
+	if _, err := group(); err != nil {
+		t.Errorf("Expected a valid status table, got %v", err)
+	}
+}
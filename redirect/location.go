@@ -0,0 +1,107 @@
+package redirect
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/MonkyMars/gecho/errors"
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// ErrUnsafeLocation is returned by SafeLocation for locations that could lead to another site or inject headers
+var ErrUnsafeLocation = stderrors.New("unsafe redirect location")
+
+// allowedHosts holds the hosts absolute locations may point to
+var allowedHosts atomic.Pointer[[]string]
+
+// SetAllowedHosts sets the hosts absolute redirect locations may point to
+// A host starting with "*." allows its subdomains only, e.g. "*.example.com" allows "api.example.com"
+// but not "example.com" itself, list the apex separately to allow it
+func SetAllowedHosts(hosts ...string) {
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		normalized = append(normalized, strings.ToLower(host))
+	}
+	allowedHosts.Store(&normalized)
+}
+
+// hostAllowed reports whether an absolute location may point to the host
+func hostAllowed(host string) bool {
+	hosts := allowedHosts.Load()
+	if hosts == nil || host == "" {
+		return false
+	}
+
+	host = strings.ToLower(host)
+	for _, allowed := range *hosts {
+		if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeLocation validates a redirect location and returns it in its escaped form
+// Relative references such as "/login?next=1" are always allowed, while absolute URLs must use
+// http or https and point to a host set with SetAllowedHosts
+// Scheme-relative ("//host"), backslash and whitespace tricks and control characters are rejected
+func SafeLocation(location string) (string, error) {
+	if location == "" || strings.TrimSpace(location) != location || strings.Contains(location, `\`) {
+		return "", ErrUnsafeLocation
+	}
+	for _, r := range location {
+		if r < 0x20 || r == 0x7f {
+			return "", ErrUnsafeLocation
+		}
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", ErrUnsafeLocation
+	}
+
+	// Relative references stay on the current site
+	if u.Scheme == "" && u.Host == "" && !strings.HasPrefix(location, "//") {
+		return u.String(), nil
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", ErrUnsafeLocation
+	}
+	if u.User != nil || !hostAllowed(u.Hostname()) {
+		return "", ErrUnsafeLocation
+	}
+
+	return u.String(), nil
+}
+
+// respond builds a redirect response, or a 400 Bad Request when the location is unsafe
+func respond(w http.ResponseWriter, status int, message, location string, opts []utils.ResponseOption) *utils.Response {
+	safe, err := SafeLocation(location)
+	if err != nil {
+		// The message and data of the redirect do not apply to the error
+		allOpts := append([]utils.ResponseOption{}, opts...)
+		allOpts = append(allOpts,
+			utils.WithMessage(utils.InvalidRedirectMessage),
+			utils.WithData(nil),
+		)
+		return errors.BadRequest(w, allOpts...)
+	}
+
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(status),
+		utils.WithMessage(message),
+		utils.WithHeader("Location", safe),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
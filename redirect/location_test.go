@@ -0,0 +1,77 @@
+package redirect
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestSafeLocation(t *testing.T) {
+	SetAllowedHosts("example.com", "*.example.org")
+	defer SetAllowedHosts()
+
+	tests := []struct {
+		location string
+		expected string
+		safe     bool
+	}{
+		{"/login", "/login", true},
+		{"/my files/a", "/my%20files/a", true},
+		{"users/42", "users/42", true},
+		{"https://example.com/path", "https://example.com/path", true},
+		{"https://api.example.org/v2", "https://api.example.org/v2", true},
+		{"https://EXAMPLE.com/", "https://EXAMPLE.com/", true},
+		{"", "", false},
+		{"//evil.com", "", false},
+		{"/\\evil.com", "", false},
+		{" //evil.com", "", false},
+		{"https://evil.com", "", false},
+		{"https://example.org", "", false}, // "*.example.org" does not allow the apex
+		{"https://a.b.example.org", "https://a.b.example.org", true},
+		{"https://evilexample.org", "", false},
+		{"https://example.com@evil.com", "", false},
+		{"https://user@example.com", "", false},
+		{"javascript:alert(1)", "", false},
+		{"https:evil.com", "", false},
+		{"/path\r\nSet-Cookie: a=b", "", false},
+		{"/path\tx", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			got, err := SafeLocation(tt.location)
+			if tt.safe {
+				if err != nil || got != tt.expected {
+					t.Errorf("Expected %q, got %q (error %v)", tt.expected, got, err)
+				}
+				return
+			}
+			if !stderrors.Is(err, ErrUnsafeLocation) {
+				t.Errorf("Expected ErrUnsafeLocation, got %q (error %v)", got, err)
+			}
+		})
+	}
+}
+
+func TestUnsafeRedirect(t *testing.T) {
+	w := httptest.NewRecorder()
+	Found(w, "https://evil.com", utils.WithData("ignored"), utils.Send())
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w.Header().Get("Location") != "" {
+		t.Errorf("Expected no Location header, got %s", w.Header().Get("Location"))
+	}
+
+	val, err := utils.ExtractResponseBody[utils.NewResponse](w.Result())
+	if err != nil {
+		t.Fatalf("Expected no error on ExtractResponseBody(), got %v", err)
+	}
+	if val.Message() != utils.InvalidRedirectMessage || val.Data() != nil {
+		t.Errorf("Expected message '%s' without data, got '%s' and %v", utils.InvalidRedirectMessage, val.Message(), val.Data())
+	}
+}
@@ -0,0 +1,39 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
+package redirect
+
+import (
+	"net/http"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+// MovedPermanently sends a 301 Moved Permanently response redirecting to location with optional configuration
+// Example: redirect.MovedPermanently(w, "/new-path", gecho.Send())
+func MovedPermanently(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.StatusMovedPermanently, utils.MovedPermanentlyMessage, location, opts)
+}
+
+// Found sends a 302 Found response redirecting to location with optional configuration
+// Example: redirect.Found(w, "/login", gecho.Send())
+func Found(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.StatusFound, utils.FoundMessage, location, opts)
+}
+
+// SeeOther sends a 303 See Other response redirecting to location with optional configuration
+// Example: redirect.SeeOther(w, "/orders/42", gecho.Send())
+func SeeOther(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.StatusSeeOther, utils.SeeOtherMessage, location, opts)
+}
+
+// TemporaryRedirect sends a 307 Temporary Redirect response redirecting to location with optional configuration
+// Example: redirect.TemporaryRedirect(w, "/maintenance", gecho.Send())
+func TemporaryRedirect(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.StatusTemporaryRedirect, utils.TemporaryRedirectMessage, location, opts)
+}
+
+// PermanentRedirect sends a 308 Permanent Redirect response redirecting to location with optional configuration
+// Example: redirect.PermanentRedirect(w, "/v2/users", gecho.Send())
+func PermanentRedirect(w http.ResponseWriter, location string, opts ...utils.ResponseOption) *utils.Response {
+	return respond(w, http.StatusPermanentRedirect, utils.PermanentRedirectMessage, location, opts)
+}
@@ -0,0 +1,47 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MonkyMars/gecho/utils"
+)
+
+func TestRedirects(t *testing.T) {
+	tests := []struct {
+		name           string
+		fn             func(http.ResponseWriter, string, ...utils.ResponseOption) *utils.Response
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{"MovedPermanently", MovedPermanently, http.StatusMovedPermanently, utils.MovedPermanentlyMessage},
+		{"Found", Found, http.StatusFound, utils.FoundMessage},
+		{"SeeOther", SeeOther, http.StatusSeeOther, utils.SeeOtherMessage},
+		{"TemporaryRedirect", TemporaryRedirect, http.StatusTemporaryRedirect, utils.TemporaryRedirectMessage},
+		{"PermanentRedirect", PermanentRedirect, http.StatusPermanentRedirect, utils.PermanentRedirectMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.fn(w, "/users/42", utils.Send())
+
+			resp := w.Result()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if location := resp.Header.Get("Location"); location != "/users/42" {
+				t.Errorf("Expected Location '/users/42', got '%s'", location)
+			}
+
+			val, err := utils.ExtractResponseBody[utils.NewResponse](resp)
+			if err != nil {
+				t.Fatalf("Expected no error on ExtractResponseBody(), got %v", err)
+			}
+			if !val.Success() || val.Message() != tt.expectedMsg {
+				t.Errorf("Expected success with message '%s', got %v and '%s'", tt.expectedMsg, val.Success(), val.Message())
+			}
+		})
+	}
+}
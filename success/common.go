@@ -1,3 +1,5 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
 package success
 
 import (
@@ -11,7 +13,7 @@ import (
 func Success(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
 	allOpts := []utils.ResponseOption{
 		utils.WithStatus(http.StatusOK),
-		utils.WithMessage("Success"),
+		utils.WithMessage(utils.SuccessMessage),
 	}
 	allOpts = append(allOpts, opts...)
 	return utils.NewOK(w, allOpts...)
@@ -22,7 +24,7 @@ func Success(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Respons
 func Created(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
 	allOpts := []utils.ResponseOption{
 		utils.WithStatus(http.StatusCreated),
-		utils.WithMessage("Resource Created"),
+		utils.WithMessage(utils.CreatedMessage),
 	}
 	allOpts = append(allOpts, opts...)
 	return utils.NewOK(w, allOpts...)
@@ -33,7 +35,18 @@ func Created(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Respons
 func Accepted(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
 	allOpts := []utils.ResponseOption{
 		utils.WithStatus(http.StatusAccepted),
-		utils.WithMessage("Accepted"),
+		utils.WithMessage(utils.AcceptedMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+
+// NonAuthoritativeInfo sends a 203 Non-Authoritative Information response with optional configuration
+// Example: success.NonAuthoritativeInfo(w, gecho.Send())
+func NonAuthoritativeInfo(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusNonAuthoritativeInfo),
+		utils.WithMessage(utils.NonAuthoritativeInfoMessage),
 	}
 	allOpts = append(allOpts, opts...)
 	return utils.NewOK(w, allOpts...)
@@ -44,7 +57,51 @@ func Accepted(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Respon
 func NoContent(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
 	allOpts := []utils.ResponseOption{
 		utils.WithStatus(http.StatusNoContent),
-		utils.WithMessage("No Content"),
+		utils.WithMessage(utils.NoContentMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+
+// ResetContent sends a 205 Reset Content response with optional configuration
+// Example: success.ResetContent(w, gecho.Send())
+func ResetContent(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusResetContent),
+		utils.WithMessage(utils.ResetContentMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+
+// PartialContent sends a 206 Partial Content response with optional configuration
+// Example: success.PartialContent(w, gecho.WithData(chunk), gecho.Send())
+func PartialContent(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusPartialContent),
+		utils.WithMessage(utils.PartialContentMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+
+// MultiStatus sends a 207 Multi-Status response with optional configuration
+// Example: success.MultiStatus(w, gecho.WithData(results), gecho.Send())
+func MultiStatus(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusMultiStatus),
+		utils.WithMessage(utils.MultiStatusMessage),
+	}
+	allOpts = append(allOpts, opts...)
+	return utils.NewOK(w, allOpts...)
+}
+
+// AlreadyReported sends a 208 Already Reported response with optional configuration
+// Example: success.AlreadyReported(w, gecho.Send())
+func AlreadyReported(w http.ResponseWriter, opts ...utils.ResponseOption) *utils.Response {
+	allOpts := []utils.ResponseOption{
+		utils.WithStatus(http.StatusAlreadyReported),
+		utils.WithMessage(utils.AlreadyReportedMessage),
 	}
 	allOpts = append(allOpts, opts...)
 	return utils.NewOK(w, allOpts...)
@@ -15,6 +15,7 @@ func TestCorrectSuccessResponses(t *testing.T) {
 		fn             func(http.ResponseWriter, ...utils.ResponseOption) *utils.Response
 		expectedStatus int
 		expectedMsg    string
+		noBody         bool
 	}{
 		{
 			name:           "Success",
@@ -39,6 +40,37 @@ func TestCorrectSuccessResponses(t *testing.T) {
 			fn:             NoContent,
 			expectedStatus: http.StatusNoContent,
 			expectedMsg:    "No Content",
+			noBody:         true,
+		},
+		{
+			name:           "NonAuthoritativeInfo",
+			fn:             NonAuthoritativeInfo,
+			expectedStatus: http.StatusNonAuthoritativeInfo,
+			expectedMsg:    utils.NonAuthoritativeInfoMessage,
+		},
+		{
+			name:           "ResetContent",
+			fn:             ResetContent,
+			expectedStatus: http.StatusResetContent,
+			expectedMsg:    utils.ResetContentMessage,
+		},
+		{
+			name:           "PartialContent",
+			fn:             PartialContent,
+			expectedStatus: http.StatusPartialContent,
+			expectedMsg:    utils.PartialContentMessage,
+		},
+		{
+			name:           "MultiStatus",
+			fn:             MultiStatus,
+			expectedStatus: http.StatusMultiStatus,
+			expectedMsg:    utils.MultiStatusMessage,
+		},
+		{
+			name:           "AlreadyReported",
+			fn:             AlreadyReported,
+			expectedStatus: http.StatusAlreadyReported,
+			expectedMsg:    utils.AlreadyReportedMessage,
 		},
 	}
 
@@ -52,6 +84,14 @@ func TestCorrectSuccessResponses(t *testing.T) {
 				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, resp.StatusCode)
 			}
 
+			// 204 No Content responses must not have a body
+			if tt.noBody {
+				if w.Body.Len() != 0 {
+					t.Errorf("Expected no body, got %s", w.Body.String())
+				}
+				return
+			}
+
 			var response utils.NewResponse
 			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 				t.Fatalf("Failed to decode response: %v", err)
@@ -0,0 +1,278 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what an asynchronous logger does when its queue is full
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = iota // Wait for room in the queue, slowing down the caller
+	OverflowDropNewest                       // Discard the entry being logged
+	OverflowDropOldest                       // Discard the oldest queued entry to make room
+)
+
+// String returns the name of the policy
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// dropReportInterval is the minimum time between two reports of dropped entries while the queue stays busy
+const dropReportInterval = time.Second
+
+// WithAsync writes entries from a background goroutine through a queue of queueSize entries
+// Logging no longer waits for the output, unless the queue is full and the policy is OverflowBlock
+// Call Flush or Close before the program exits, Fatal flushes the queue itself
+func WithAsync(queueSize int, policy OverflowPolicy) LoggerOptions {
+	return func(c *Config) {
+		c.QueueSize = queueSize
+		c.Overflow = policy
+	}
+}
+
+// asyncRecord is a formatted entry waiting to be written
+type asyncRecord struct {
+	w    io.Writer
+	data []byte
+}
+
+// asyncQueue is the bounded queue of an asynchronous logger, shared by the loggers derived from it
+type asyncQueue struct {
+	mu        sync.Mutex
+	notEmpty  *sync.Cond
+	notFull   *sync.Cond
+	records   []asyncRecord
+	size      int
+	policy    OverflowPolicy
+	closed    bool
+	enqueued  uint64        // Records accepted by the queue
+	processed uint64        // Records written or dropped from the queue
+	progress  chan struct{} // Closed and replaced whenever processed grows
+	done      chan struct{} // Closed when the writer goroutine exits
+
+	dropped atomic.Uint64 // Entries dropped since the last report
+	total   atomic.Uint64 // Entries dropped since the queue was created
+	report  func(dropped uint64)
+}
+
+// newAsyncQueue creates a queue and starts its writer goroutine
+func newAsyncQueue(size int, policy OverflowPolicy, report func(dropped uint64)) *asyncQueue {
+	q := &asyncQueue{
+		records:  make([]asyncRecord, 0, size),
+		size:     size,
+		policy:   policy,
+		progress: make(chan struct{}),
+		done:     make(chan struct{}),
+		report:   report,
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// enqueue adds a record, applying the overflow policy when the queue is full
+// It returns false once the queue is closed, the caller then writes the record itself
+func (q *asyncQueue) enqueue(record asyncRecord) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.records) >= q.size && !q.closed {
+		switch q.policy {
+		case OverflowDropNewest:
+			q.drop()
+			return true
+		case OverflowDropOldest:
+			q.records = q.records[1:]
+			q.drop()
+			q.advance()
+		default:
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+
+	q.records = append(q.records, record)
+	q.enqueued++
+	q.notEmpty.Signal()
+	return true
+}
+
+// drop counts a dropped entry
+func (q *asyncQueue) drop() {
+	q.dropped.Add(1)
+	q.total.Add(1)
+}
+
+// advance marks a record as processed, the caller must hold q.mu
+func (q *asyncQueue) advance() {
+	q.processed++
+	close(q.progress)
+	q.progress = make(chan struct{})
+}
+
+// run writes the queued records until the queue is closed and drained
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	lastReport := time.Now()
+
+	for {
+		q.mu.Lock()
+		for len(q.records) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.records) == 0 {
+			q.mu.Unlock()
+			q.reportDropped()
+			return
+		}
+		record := q.records[0]
+		q.records[0] = asyncRecord{}
+		q.records = q.records[1:]
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		record.w.Write(record.data)
+
+		q.mu.Lock()
+		idle := len(q.records) == 0
+		q.mu.Unlock()
+
+		// Drops are reported once the queue catches up, or periodically while it stays busy
+		if idle || time.Since(lastReport) >= dropReportInterval {
+			q.reportDropped()
+			lastReport = time.Now()
+		}
+
+		q.mu.Lock()
+		q.advance()
+		q.mu.Unlock()
+	}
+}
+
+// reportDropped logs the number of entries dropped since the last report
+func (q *asyncQueue) reportDropped() {
+	if n := q.dropped.Swap(0); n > 0 && q.report != nil {
+		q.report(n)
+	}
+}
+
+// flush waits until the records queued before the call are written
+func (q *asyncQueue) flush(ctx context.Context) error {
+	q.mu.Lock()
+	target := q.enqueued
+	for q.processed < target {
+		progress := q.progress
+		q.mu.Unlock()
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	q.mu.Unlock()
+	return nil
+}
+
+// close stops accepting records and waits for the queued ones to be written
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+	<-q.done
+}
+
+// queues returns the queue of the logger followed by those of its sinks
+// The logger queue comes first, as its records end up in the sink queues
+func (l *Logger) queues() []*asyncQueue {
+	var queues []*asyncQueue
+	if l.async != nil {
+		queues = append(queues, l.async)
+	}
+	for _, sw := range l.sinks {
+		if sw.queue != nil {
+			queues = append(queues, sw.queue)
+		}
+	}
+	return queues
+}
+
+// Flush waits until the entries logged before the call are written, or ctx is done
+// It returns immediately for synchronous loggers
+func (l *Logger) Flush(ctx context.Context) error {
+	for _, q := range l.queues() {
+		if err := q.flush(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the queued entries and stops the writer goroutines of the logger and its sinks
+// The queues are shared with the loggers derived from it, which write synchronously afterwards
+func (l *Logger) Close() error {
+	for _, q := range l.queues() {
+		q.close()
+	}
+	return nil
+}
+
+// Dropped returns the number of entries the logger and its sinks have dropped because a queue was full
+func (l *Logger) Dropped() uint64 {
+	var total uint64
+	for _, q := range l.queues() {
+		total += q.total.Load()
+	}
+	return total
+}
+
+// reportDropped writes a warning with the number of dropped entries, bypassing the queue
+func (l *Logger) reportDropped(dropped uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := droppedEntry(dropped, l.config.Overflow, l.config.TimeFormat)
+	if len(l.sinks) == 0 {
+		l.config.Output.Write(formatEntry(l.formatter(), e))
+		return
+	}
+	for _, sw := range l.sinks {
+		if sw.accepts(e) {
+			sw.w.Write(formatEntry(sw.formatter(), e))
+		}
+	}
+}
+
+// droppedEntry returns the warning reporting entries dropped by a queue with the policy
+func droppedEntry(dropped uint64, policy OverflowPolicy, timeFormat string) *Entry {
+	now := time.Now()
+	return &Entry{
+		Time:      now,
+		Timestamp: now.Format(timeFormat),
+		Level:     LevelWarn,
+		Message:   "Log entries dropped",
+		Fields: []FieldPair{
+			{Key: "dropped", Value: dropped},
+			{Key: "policy", Value: policy.String()},
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks every write until its gate is opened
+type gatedWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{entered: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.entered <- struct{}{}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// asyncLogger returns an asynchronous text logger writing to w, closed at the end of the test
+func asyncLogger(t *testing.T, w *gatedWriter, size int, policy OverflowPolicy) *Logger {
+	t.Helper()
+	logger := NewLogger(NewConfig(WithOutput(w), WithLogFormat(FormatText), WithShowCaller(false), WithAsync(size, policy)))
+	t.Cleanup(func() { logger.Close() })
+	return logger
+}
+
+func TestAsyncLoggerDoesNotWait(t *testing.T) {
+	w := newGatedWriter()
+	logger := asyncLogger(t, w, 10, OverflowBlock)
+
+	logger.Info("first")
+	logger.WithField("child", true).Info("second")
+	<-w.entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := logger.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Flush to give up with the context, got %v", err)
+	}
+
+	close(w.gate)
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "first") || !strings.Contains(w.String(), "second {child=true}") {
+		t.Errorf("Expected both entries after Flush, got %q", w.String())
+	}
+}
+
+func TestAsyncLoggerOverflow(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		kept   []string
+		lost   []string
+	}{
+		{OverflowDropNewest, []string{"entry-1", "entry-2", "entry-3"}, []string{"entry-4", "entry-5"}},
+		{OverflowDropOldest, []string{"entry-1", "entry-4", "entry-5"}, []string{"entry-2", "entry-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			w := newGatedWriter()
+			logger := asyncLogger(t, w, 2, tt.policy)
+
+			// The first entry is held by the writer, the queue holds two more
+			logger.Info("entry-1")
+			<-w.entered
+			for _, msg := range []string{"entry-2", "entry-3", "entry-4", "entry-5"} {
+				logger.Info(msg)
+			}
+			close(w.gate)
+			logger.Flush(context.Background())
+
+			out := w.String()
+			for _, msg := range tt.kept {
+				if !strings.Contains(out, msg) {
+					t.Errorf("Expected %s to be written, got %q", msg, out)
+				}
+			}
+			for _, msg := range tt.lost {
+				if strings.Contains(out, msg) {
+					t.Errorf("Expected %s to be dropped, got %q", msg, out)
+				}
+			}
+			if !strings.Contains(out, "Log entries dropped {dropped=2, policy="+tt.policy.String()+"}") {
+				t.Errorf("Expected the dropped entries to be reported, got %q", out)
+			}
+			if logger.Dropped() != 2 {
+				t.Errorf("Expected 2 dropped entries, got %d", logger.Dropped())
+			}
+		})
+	}
+}
+
+func TestAsyncLoggerBlock(t *testing.T) {
+	w := newGatedWriter()
+	logger := asyncLogger(t, w, 1, OverflowBlock)
+
+	logger.Info("entry-1")
+	<-w.entered
+	logger.Info("entry-2")
+
+	logged := make(chan struct{})
+	go func() {
+		logger.Info("entry-3")
+		close(logged)
+	}()
+
+	select {
+	case <-logged:
+		t.Fatal("Expected the caller to wait for room in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(w.gate)
+	<-logged
+	logger.Flush(context.Background())
+	if strings.Count(w.String(), "entry-") != 3 || logger.Dropped() != 0 {
+		t.Errorf("Expected every entry to be written, got %q", w.String())
+	}
+}
+
+func TestAsyncLoggerClose(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	logger := asyncLogger(t, w, 10, OverflowBlock)
+
+	logger.Info("queued")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "queued") {
+		t.Errorf("Expected Close to write the queued entries, got %q", w.String())
+	}
+
+	logger.Info("after close")
+	if !strings.Contains(w.String(), "after close") {
+		t.Errorf("Expected entries after Close to be written synchronously, got %q", w.String())
+	}
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Errorf("Expected Flush after Close to succeed, got %v", err)
+	}
+}
+
+func TestAsyncLoggerFatalFlushes(t *testing.T) {
+	if os.Getenv("GECHO_TEST_FATAL") == "1" {
+		logger := NewLogger(NewConfig(WithOutput(os.Stdout), WithErrorOutput(os.Stdout), WithLogFormat(FormatText), WithAsync(100, OverflowBlock)))
+		for range 50 {
+			logger.Info("queued")
+		}
+		logger.Fatal("fatal")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestAsyncLoggerFatalFlushes$")
+	cmd.Env = append(os.Environ(), "GECHO_TEST_FATAL=1")
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit status 1, got %v", err)
+	}
+	if strings.Count(string(out), "queued") != 50 || !strings.Contains(string(out), "fatal") {
+		t.Errorf("Expected every entry before the exit, got %q", out)
+	}
+}
@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// conditionalOptions holds the validators of a response used for conditional requests
+type conditionalOptions struct {
+	etag         string
+	autoETag     bool
+	weak         bool
+	lastModified time.Time
+}
+
+// enabled reports whether the response has any validator
+func (co conditionalOptions) enabled() bool {
+	return co.etag != "" || co.autoETag || !co.lastModified.IsZero()
+}
+
+// WithETag sets the entity tag of the response
+// The tag may be given quoted, weak (W/"...") or as a bare value, which is quoted as a strong tag
+func WithETag(etag string) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.conditional.etag = formatETag(etag)
+	}
+}
+
+// WithAutoETag computes the entity tag from the encoded response, excluding the timestamp
+// Weak tags only promise semantic equivalence and are enough for caching,
+// strong tags also allow If-Match on unsafe methods
+func WithAutoETag(weak bool) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.conditional.autoETag = true
+		rc.conditional.weak = weak
+	}
+}
+
+// WithLastModified sets the modification time of the response, sent with second precision
+func WithLastModified(t time.Time) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.conditional.lastModified = t.UTC().Truncate(time.Second)
+	}
+}
+
+// formatETag quotes a bare entity tag, leaving quoted and weak tags untouched
+func formatETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `W/"`) || strings.HasPrefix(etag, `"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// computeETag hashes the document as encoded for the media type, without the volatile timestamp and request ID
+func computeETag(doc Document, mediaType string, weak bool) (string, error) {
+	if nr, ok := doc.(NewResponse); ok {
+		schema := nr.envelopeSchema()
+		// The timestamp and request ID differ between otherwise identical responses
+		schema.TimestampField = OmitField
+		schema.RequestIDField = OmitField
+		nr.schema = &schema
+		doc = nr
+	}
+
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(mediaType))
+	hash.Write([]byte{0})
+	hash.Write(payload)
+
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+	return etag, nil
+}
+
+// validators returns the ETag and Last-Modified of the response for the negotiated media type
+func (co conditionalOptions) validators(doc Document, mediaType string) (string, time.Time, error) {
+	etag := co.etag
+	if etag == "" && co.autoETag {
+		var err error
+		if etag, err = computeETag(doc, mediaType, co.weak); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	return etag, co.lastModified, nil
+}
+
+// evaluatePreconditions evaluates the conditional headers of the request as described in RFC 9110
+// It returns 304 Not Modified, 412 Precondition Failed, or 0 when the request should proceed
+// If-Match and If-Unmodified-Since are only evaluated for unsafe methods
+func evaluatePreconditions(r *http.Request, etag string, lastModified time.Time) int {
+	if !isSafeMethod(r.Method) {
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+			if !matchETag(ifMatch, etag, true) {
+				return http.StatusPreconditionFailed
+			}
+		} else if since, ok := parseHTTPTime(r.Header.Get("If-Unmodified-Since")); ok && !lastModified.IsZero() {
+			if lastModified.After(since) {
+				return http.StatusPreconditionFailed
+			}
+		}
+	}
+
+	return evaluateNoneMatch(r, etag, lastModified)
+}
+
+// evaluateNoneMatch evaluates If-None-Match, or If-Modified-Since without it
+// A match answers 304 Not Modified for GET and HEAD and 412 Precondition Failed for other methods
+func evaluateNoneMatch(r *http.Request, etag string, lastModified time.Time) int {
+	safe := isSafeMethod(r.Method)
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if matchETag(ifNoneMatch, etag, false) {
+			if safe {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+		return 0
+	}
+
+	if safe {
+		if since, ok := parseHTTPTime(r.Header.Get("If-Modified-Since")); ok && !lastModified.IsZero() {
+			if !lastModified.After(since) {
+				return http.StatusNotModified
+			}
+		}
+	}
+
+	return 0
+}
+
+// isSafeMethod reports whether the method is GET or HEAD, the methods answered with 304 Not Modified
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// CheckPreconditions evaluates the conditional headers of the request against the current validators of the resource
+// Call it before changing the resource, with an empty etag or zero lastModified for the validators it does not have
+// When a precondition fails it sends 412 Precondition Failed, or 304 Not Modified for GET and HEAD, and returns false
+// Example: if !utils.CheckPreconditions(w, r, user.ETag(), user.UpdatedAt) { return }
+func CheckPreconditions(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	if w == nil {
+		panic("http.ResponseWriter is nil")
+	}
+	if r == nil {
+		return true
+	}
+
+	etag = formatETag(etag)
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	switch evaluatePreconditions(r, etag, lastModified) {
+	case http.StatusNotModified:
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		if !lastModified.IsZero() {
+			w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		}
+		addVary(w, "Accept")
+		w.WriteHeader(http.StatusNotModified)
+		return false
+	case http.StatusPreconditionFailed:
+		NewErrFor(w, r, WithStatus(http.StatusPreconditionFailed), WithMessage(PreconditionFailedMessage), Send())
+		return false
+	}
+	return true
+}
+
+// matchETag reports whether the entity tag matches the list of an If-Match or If-None-Match header
+// Strong comparison requires both tags to be strong, weak comparison ignores the W/ prefix
+func matchETag(header, etag string, strong bool) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	weak := strings.HasPrefix(etag, "W/")
+	opaque := strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		candidateWeak := strings.HasPrefix(candidate, "W/")
+		if strong && (weak || candidateWeak) {
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTTPTime parses an HTTP date header, ok is false when it is absent or invalid
+func parseHTTPTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// sendConditional sends a response with validators, answering 304 to GET and HEAD requests for an unchanged resource
+// Only 2xx responses are conditional, others are sent as they are
+func (r *Response) sendConditional(doc Document) error {
+	if r.status < 200 || r.status >= 300 {
+		return writeResponse(r.w, r.req, r.status, r.headers, doc)
+	}
+
+	accept := ""
+	if r.req != nil {
+		accept = r.req.Header.Get("Accept")
+	}
+
+	// Not acceptable responses carry no validators
+	mediaType, _, ok := encoders.negotiate(accept)
+	if !ok {
+		return writeResponse(r.w, r.req, r.status, r.headers, doc)
+	}
+
+	// A document that cannot be hashed cannot be encoded either, writeResponse reports the failure
+	etag, lastModified, err := r.conditional.validators(doc, mediaType)
+	if err != nil {
+		return writeResponse(r.w, r.req, r.status, r.headers, doc)
+	}
+
+	headers := r.headers.Clone()
+	if etag != "" {
+		headers.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		headers.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	// If-Match and If-Unmodified-Since concern the resource before a change, see CheckPreconditions
+	if r.req == nil || !isSafeMethod(r.req.Method) {
+		return writeResponse(r.w, r.req, r.status, headers, doc)
+	}
+
+	if evaluateNoneMatch(r.req, etag, lastModified) == http.StatusNotModified {
+		return writeResponse(r.w, r.req, http.StatusNotModified, headers, doc)
+	}
+
+	return writeResponse(r.w, r.req, r.status, headers, doc)
+}
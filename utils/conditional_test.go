@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAutoETag(t *testing.T) {
+	send := func(r *http.Request, data any, opts ...ResponseOption) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		allOpts := append([]ResponseOption{WithRequest(r), WithData(data), WithAutoETag(false), Send()}, opts...)
+		NewOK(w, allOpts...)
+		return w
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	first := send(r, map[string]string{"name": "Alice"})
+	etag := first.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`) || len(etag) < 10 {
+		t.Fatalf("Expected a strong ETag, got %q", etag)
+	}
+
+	// The timestamp changes between responses, the ETag must not
+	time.Sleep(2 * time.Millisecond)
+	if second := send(r, map[string]string{"name": "Alice"}); second.Header().Get("ETag") != etag {
+		t.Errorf("Expected a stable ETag, got %q and %q", etag, second.Header().Get("ETag"))
+	}
+
+	if changed := send(r, map[string]string{"name": "Bob"}); changed.Header().Get("ETag") == etag {
+		t.Error("Expected the ETag to change with the data")
+	}
+
+	xml := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	xml.Header.Set("Accept", MediaTypeXML)
+	if other := send(xml, map[string]string{"name": "Alice"}); other.Header().Get("ETag") == etag {
+		t.Error("Expected the ETag to differ between representations")
+	}
+
+	t.Run("Weak", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewOK(w, WithData("x"), WithAutoETag(true), Send())
+		if etag := w.Header().Get("ETag"); !strings.HasPrefix(etag, `W/"`) {
+			t.Errorf("Expected a weak ETag, got %q", etag)
+		}
+	})
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		expected    int
+	}{
+		{"Match", http.MethodGet, `"v1"`, http.StatusNotModified},
+		{"WeakMatch", http.MethodGet, `W/"v1"`, http.StatusNotModified},
+		{"ListMatch", http.MethodGet, `"v0", "v1"`, http.StatusNotModified},
+		{"Wildcard", http.MethodHead, `*`, http.StatusNotModified},
+		{"NoMatch", http.MethodGet, `"v2"`, http.StatusOK},
+		{"UnsafeIgnored", http.MethodPut, `"v1"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/users/1", nil)
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+
+			NewOK(w, WithRequest(r), WithData("Alice"), WithETag("v1"), Send())
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if tt.expected == http.StatusNotModified {
+				if w.Body.Len() != 0 {
+					t.Errorf("Expected no body, got %s", w.Body.String())
+				}
+				if w.Header().Get("ETag") != `"v1"` {
+					t.Errorf("Expected ETag on 304, got %q", w.Header().Get("ETag"))
+				}
+				if w.Header().Get("Content-Type") != "" {
+					t.Errorf("Expected no Content-Type on 304, got %q", w.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestIfModifiedSince(t *testing.T) {
+	modified := time.Date(2024, 1, 15, 10, 30, 45, 500, time.UTC)
+
+	tests := []struct {
+		name     string
+		since    time.Time
+		expected int
+	}{
+		{"NotModified", modified, http.StatusNotModified},
+		{"Modified", modified.Add(-time.Hour), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			r.Header.Set("If-Modified-Since", tt.since.Format(http.TimeFormat))
+
+			NewOK(w, WithRequest(r), WithLastModified(modified), Send())
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if lm := w.Header().Get("Last-Modified"); lm != modified.Format(http.TimeFormat) {
+				t.Errorf("Expected Last-Modified %s, got %s", modified.Format(http.TimeFormat), lm)
+			}
+		})
+	}
+
+	t.Run("IfNoneMatchTakesPrecedence", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		r.Header.Set("If-None-Match", `"other"`)
+		r.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+
+		NewOK(w, WithRequest(r), WithETag("v1"), WithLastModified(modified), Send())
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	modified := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		method   string
+		etag     string
+		header   string
+		value    string
+		expected int
+	}{
+		{"IfMatch", http.MethodPut, "v1", "If-Match", `"v1"`, http.StatusOK},
+		{"IfMatchMismatch", http.MethodPut, "v1", "If-Match", `"v2"`, http.StatusPreconditionFailed},
+		{"IfMatchWeakNeverMatches", http.MethodPatch, `W/"v1"`, "If-Match", `W/"v1"`, http.StatusPreconditionFailed},
+		{"IfMatchWildcard", http.MethodDelete, "v1", "If-Match", `*`, http.StatusOK},
+		{"IfMatchWildcardMissing", http.MethodPut, "", "If-Match", `*`, http.StatusPreconditionFailed},
+		{"IfMatchSafeMethodIgnored", http.MethodGet, "v1", "If-Match", `"v2"`, http.StatusOK},
+		{"IfUnmodifiedSince", http.MethodPut, "", "If-Unmodified-Since", modified.Format(http.TimeFormat), http.StatusOK},
+		{"IfUnmodifiedSinceOlder", http.MethodPut, "", "If-Unmodified-Since", modified.Add(-time.Minute).Format(http.TimeFormat), http.StatusPreconditionFailed},
+		{"IfNoneMatchUnsafe", http.MethodPut, "v1", "If-None-Match", `*`, http.StatusPreconditionFailed},
+		{"IfNoneMatchUnsafeMissing", http.MethodPut, "", "If-None-Match", `*`, http.StatusOK},
+		{"IfNoneMatchSafe", http.MethodGet, "v1", "If-None-Match", `"v1"`, http.StatusNotModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/users/1", nil)
+			r.Header.Set(tt.header, tt.value)
+
+			proceed := CheckPreconditions(w, r, tt.etag, modified)
+			if proceed != (tt.expected == http.StatusOK) {
+				t.Fatalf("Expected CheckPreconditions() to return %v", !proceed)
+			}
+
+			switch tt.expected {
+			case http.StatusOK:
+				if w.Body.Len() != 0 || len(w.Header()) != 0 {
+					t.Errorf("Expected nothing to be written, got %v %s", w.Header(), w.Body.String())
+				}
+			case http.StatusPreconditionFailed:
+				if w.Code != http.StatusPreconditionFailed {
+					t.Errorf("Expected status 412, got %d", w.Code)
+				}
+				if !strings.Contains(w.Body.String(), PreconditionFailedMessage) {
+					t.Errorf("Expected precondition failed envelope, got %s", w.Body.String())
+				}
+			case http.StatusNotModified:
+				if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+					t.Errorf("Expected an empty 304, got %d %s", w.Code, w.Body.String())
+				}
+				if w.Header().Get("ETag") != `"v1"` {
+					t.Errorf("Expected ETag on 304, got %q", w.Header().Get("ETag"))
+				}
+			}
+		})
+	}
+
+	// The precondition concerns the resource before the change, the response carries the new validators
+	t.Run("ResponseAfterChange", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+		r.Header.Set("If-Match", `"v1"`)
+		r.Header.Set("If-Unmodified-Since", modified.Format(http.TimeFormat))
+
+		if !CheckPreconditions(w, r, "v1", modified) {
+			t.Fatal("Expected the current ETag to pass")
+		}
+		NewOK(w, WithRequest(r), WithData("Bob"), WithETag("v2"), WithLastModified(modified.Add(time.Hour)), Send())
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w.Header().Get("ETag") != `"v2"` {
+			t.Errorf("Expected the new ETag, got %q", w.Header().Get("ETag"))
+		}
+	})
+}
+
+func TestConditionalSkipsErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("If-None-Match", "*")
+
+	NewErr(w, WithRequest(r), WithStatus(http.StatusNotFound), WithETag("v1"), Send())
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Errorf("Expected no ETag on an error response, got %q", w.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Media types of the built-in encoders
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+)
+
+// Document is a response body handed to an Encoder, either a NewResponse or a ProblemDetails
+type Document interface {
+	json.Marshaler
+	xml.Marshaler
+	// Map returns the document as a generic map, for encoders that cannot use the marshalers
+	Map() map[string]any
+}
+
+// Encoder encodes a response document into a specific media type
+type Encoder interface {
+	Encode(w io.Writer, doc Document) error
+}
+
+// EncoderFunc adapts an ordinary function to the Encoder interface
+type EncoderFunc func(w io.Writer, doc Document) error
+
+// Encode calls f(w, doc)
+func (f EncoderFunc) Encode(w io.Writer, doc Document) error {
+	return f(w, doc)
+}
+
+// JSONEncoder encodes documents using encoding/json
+var JSONEncoder = EncoderFunc(func(w io.Writer, doc Document) error {
+	return json.NewEncoder(w).Encode(doc)
+})
+
+// XMLEncoder encodes documents using encoding/xml
+var XMLEncoder = EncoderFunc(func(w io.Writer, doc Document) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(doc)
+})
+
+// encoderRegistry holds the encoders available for content negotiation
+type encoderRegistry struct {
+	mu          sync.RWMutex
+	encoders    map[string]Encoder
+	order       []string
+	defaultType string
+}
+
+var encoders = &encoderRegistry{
+	encoders: map[string]Encoder{
+		MediaTypeJSON: JSONEncoder,
+		MediaTypeXML:  XMLEncoder,
+	},
+	order:       []string{MediaTypeJSON, MediaTypeXML},
+	defaultType: MediaTypeJSON,
+}
+
+// RegisterEncoder registers an encoder for a media type, replacing any existing one
+// Example: utils.RegisterEncoder("application/yaml", yamlEncoder)
+func RegisterEncoder(mediaType string, enc Encoder) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	encoders.mu.Lock()
+	defer encoders.mu.Unlock()
+
+	if _, exists := encoders.encoders[mediaType]; !exists {
+		encoders.order = append(encoders.order, mediaType)
+	}
+	encoders.encoders[mediaType] = enc
+}
+
+// SetDefaultEncoder sets the media type used when the request does not express a preference
+// Unregistered media types are ignored
+func SetDefaultEncoder(mediaType string) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	encoders.mu.Lock()
+	defer encoders.mu.Unlock()
+
+	if _, exists := encoders.encoders[mediaType]; exists {
+		encoders.defaultType = mediaType
+	}
+}
+
+// RegisteredMediaTypes returns the registered media types in registration order
+func RegisteredMediaTypes() []string {
+	encoders.mu.RLock()
+	defer encoders.mu.RUnlock()
+
+	return append([]string(nil), encoders.order...)
+}
+
+// defaultEncoder returns the default media type and its encoder
+func (er *encoderRegistry) defaultEncoder() (string, Encoder) {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	return er.defaultType, er.encoders[er.defaultType]
+}
+
+// acceptRange is a single media range from an Accept header
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept parses an Accept header into media ranges, skipping invalid ones
+func parseAccept(header string) []acceptRange {
+	ranges := make([]acceptRange, 0)
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: normalizeMediaType(mediaType), quality: quality})
+	}
+	return ranges
+}
+
+// normalizeMediaType maps problem media types onto the encoder that renders them
+func normalizeMediaType(mediaType string) string {
+	switch mediaType {
+	case ProblemContentType:
+		return MediaTypeJSON
+	case ProblemXMLContentType:
+		return MediaTypeXML
+	}
+	return mediaType
+}
+
+// matchSpecificity reports how specifically a media range matches a media type, 0 means no match
+func matchSpecificity(mediaRange, mediaType string) int {
+	if mediaRange == mediaType {
+		return 3
+	}
+	if mediaRange == "*/*" {
+		return 1
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+		return 2
+	}
+	return 0
+}
+
+// negotiate picks the registered encoder that best satisfies the Accept header
+// An empty header selects the default encoder, ok is false when nothing is acceptable
+func (er *encoderRegistry) negotiate(accept string) (string, Encoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		mediaType, enc := er.defaultEncoder()
+		return mediaType, enc, true
+	}
+
+	ranges := parseAccept(accept)
+
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+
+	bestType := ""
+	bestQuality := 0.0
+	for _, mediaType := range er.order {
+		// The most specific matching range determines the quality of a media type
+		specificity, quality := 0, 0.0
+		for _, ar := range ranges {
+			if s := matchSpecificity(ar.mediaType, mediaType); s > specificity {
+				specificity, quality = s, ar.quality
+			}
+		}
+
+		if specificity == 0 || quality <= 0 {
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && mediaType == er.defaultType) {
+			bestType, bestQuality = mediaType, quality
+		}
+	}
+
+	if bestType == "" {
+		return "", nil, false
+	}
+	return bestType, er.encoders[bestType], true
+}
+
+// xmlName turns an arbitrary key into a valid XML element name
+func xmlName(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(i > 0 && (r == '-' || r == '.' || (r >= '0' && r <= '9')))
+		if valid {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
+
+// encodeXMLValue encodes an arbitrary value as an XML element
+// Maps become nested elements, slices become repeated <item> elements
+func encodeXMLValue(e *xml.Encoder, name string, v any) error {
+	if v == nil {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if _, ok := v.(xml.Marshaler); ok {
+		return e.EncodeElement(v, start)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return e.EncodeElement(v, start)
+		}
+
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			value := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface()
+			if err := encodeXMLValue(e, key, value); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return e.EncodeElement(v, start)
+		}
+
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := encodeXMLValue(e, "item", rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return e.EncodeToken(start.End())
+	default:
+		return e.EncodeElement(rv.Interface(), start)
+	}
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{"Empty", "", MediaTypeJSON, true},
+		{"Wildcard", "*/*", MediaTypeJSON, true},
+		{"ExactXML", "application/xml", MediaTypeXML, true},
+		{"QualityOrdering", "application/json;q=0.5, application/xml", MediaTypeXML, true},
+		{"TypeWildcard", "text/html, application/*;q=0.8", MediaTypeJSON, true},
+		{"ProblemJSON", "application/problem+json", MediaTypeJSON, true},
+		{"ExcludedByZeroQuality", "application/json;q=0, application/xml;q=0", "", false},
+		{"Unsupported", "text/html", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaType, _, ok := encoders.negotiate(tt.accept)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+			if mediaType != tt.expected {
+				t.Errorf("Expected media type '%s', got '%s'", tt.expected, mediaType)
+			}
+		})
+	}
+}
+
+func TestVaryKeepsResponseHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	NewOKFor(w, r).
+		AddHeader("Vary", "Origin").
+		AddHeader("Vary", "accept").
+		Send()
+
+	vary := w.Header().Values("Vary")
+	if len(vary) != 2 || vary[0] != "Origin" || vary[1] != "accept" {
+		t.Errorf("Expected Vary [Origin accept], got %v", vary)
+	}
+
+	w = httptest.NewRecorder()
+	NewOKFor(w, r, WithHeader("Vary", "Origin"), Send())
+	if vary := w.Header().Values("Vary"); len(vary) != 2 || vary[0] != "Origin" || vary[1] != "Accept" {
+		t.Errorf("Expected Vary [Origin Accept], got %v", vary)
+	}
+}
+
+func TestSendNegotiatesXML(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Accept", "application/xml")
+
+	NewOKFor(w, r,
+		WithData(map[string]any{"name": "alice", "roles": []string{"admin", "dev"}}),
+		Send(),
+	)
+
+	resp := w.Result()
+	if ct := resp.Header.Get("Content-Type"); ct != MediaTypeXML {
+		t.Fatalf("Expected Content-Type '%s', got '%s'", MediaTypeXML, ct)
+	}
+
+	if vary := resp.Header.Get("Vary"); vary != "Accept" {
+		t.Errorf("Expected Vary 'Accept', got '%s'", vary)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"response"`
+		Status  int      `xml:"status"`
+		Success bool     `xml:"success"`
+		Data    struct {
+			Name  string   `xml:"name"`
+			Roles []string `xml:"roles>item"`
+		} `xml:"data"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode XML response: %v", err)
+	}
+
+	if doc.Status != http.StatusOK || !doc.Success {
+		t.Errorf("Expected status 200 and success, got %d and %v", doc.Status, doc.Success)
+	}
+
+	if doc.Data.Name != "alice" || len(doc.Data.Roles) != 2 {
+		t.Errorf("Expected data to round-trip, got %+v", doc.Data)
+	}
+}
+
+func TestSendNotAcceptable(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Accept", "text/html")
+
+	NewOKFor(w, r, Send())
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotAcceptable, resp.StatusCode)
+	}
+
+	var response NewResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Success() || response.Message() != NotAcceptableMessage {
+		t.Errorf("Expected not acceptable envelope, got %+v", response)
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	const mediaType = "text/plain"
+	RegisterEncoder(mediaType, EncoderFunc(func(w io.Writer, doc Document) error {
+		m := doc.Map()
+		_, err := fmt.Fprintf(w, "%v %v", m["status"], m["message"])
+		return err
+	}))
+	defer func() {
+		encoders.mu.Lock()
+		delete(encoders.encoders, mediaType)
+		encoders.order = encoders.order[:len(encoders.order)-1]
+		encoders.mu.Unlock()
+	}()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/plain")
+
+	NewErrFor(w, r, WithStatus(http.StatusNotFound), WithMessage("missing"), Send())
+
+	if body := strings.TrimSpace(w.Body.String()); body != "404 missing" {
+		t.Errorf("Expected custom encoder output '404 missing', got '%s'", body)
+	}
+}
+
+func TestProblemXML(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+
+	NewErrFor(w, r, WithErrorFormat(ErrorFormatProblem), WithStatus(http.StatusForbidden), Send())
+
+	if ct := w.Result().Header.Get("Content-Type"); ct != ProblemXMLContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", ProblemXMLContentType, ct)
+	}
+
+	if !strings.Contains(w.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`) {
+		t.Errorf("Expected problem root element, got %s", w.Body.String())
+	}
+}
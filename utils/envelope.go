@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// OmitField can be used as a field name in an EnvelopeSchema to leave the field out
+const OmitField = "-"
+
+// EnvelopeSchema describes the field names of the response envelope
+// Empty names fall back to the defaults, OmitField leaves the field out entirely
+type EnvelopeSchema struct {
+	StatusField    string         // Default: "status"
+	SuccessField   string         // Default: "success"
+	MessageField   string         // Default: "message"
+	DataField      string         // Default: "data"
+	MetaField      string         // Default: "meta"
+	RequestIDField string         // Default: "request_id", only sent when the request carries an ID
+	TimestampField string         // Default: "timestamp"
+	Static         map[string]any // Fields added to every envelope, e.g. {"api_version": "v2"}
+}
+
+// DefaultEnvelopeSchema returns the schema of the standard gecho envelope
+func DefaultEnvelopeSchema() EnvelopeSchema {
+	return EnvelopeSchema{
+		StatusField:    "status",
+		SuccessField:   "success",
+		MessageField:   "message",
+		DataField:      "data",
+		MetaField:      "meta",
+		RequestIDField: RequestIDField,
+		TimestampField: "timestamp",
+	}
+}
+
+// envelopeSchema holds the global schema, shared by all responses
+var envelopeSchema atomic.Pointer[EnvelopeSchema]
+
+// SetEnvelopeSchema sets the global envelope schema used to encode and decode responses
+func SetEnvelopeSchema(schema EnvelopeSchema) {
+	schema = schema.Normalize()
+	envelopeSchema.Store(&schema)
+}
+
+// GetEnvelopeSchema returns the global envelope schema
+func GetEnvelopeSchema() EnvelopeSchema {
+	if schema := envelopeSchema.Load(); schema != nil {
+		return *schema
+	}
+	return DefaultEnvelopeSchema()
+}
+
+// WithEnvelopeSchema overrides the global envelope schema for a single response
+func WithEnvelopeSchema(schema EnvelopeSchema) ResponseOption {
+	return func(rc *responseConfig) {
+		schema = schema.Normalize()
+		rc.schema = &schema
+	}
+}
+
+// Normalize returns the schema with empty field names replaced by their defaults
+func (s EnvelopeSchema) Normalize() EnvelopeSchema {
+	defaults := DefaultEnvelopeSchema()
+	if s.StatusField == "" {
+		s.StatusField = defaults.StatusField
+	}
+	if s.SuccessField == "" {
+		s.SuccessField = defaults.SuccessField
+	}
+	if s.MessageField == "" {
+		s.MessageField = defaults.MessageField
+	}
+	if s.DataField == "" {
+		s.DataField = defaults.DataField
+	}
+	if s.MetaField == "" {
+		s.MetaField = defaults.MetaField
+	}
+	if s.RequestIDField == "" {
+		s.RequestIDField = defaults.RequestIDField
+	}
+	if s.TimestampField == "" {
+		s.TimestampField = defaults.TimestampField
+	}
+	s.Static = maps.Clone(s.Static)
+	return s
+}
+
+// envelopeField is a single named field of an encoded envelope
+type envelopeField struct {
+	name  string
+	value any
+}
+
+// fields returns the envelope fields of a response in schema order
+// Omitted fields, nil data, empty meta, a missing request ID and static fields shadowed by a named field are skipped
+func (s EnvelopeSchema) fields(nr NewResponse) []envelopeField {
+	fields := make([]envelopeField, 0, 7+len(s.Static))
+	used := make(map[string]bool)
+
+	add := func(name string, value any) {
+		if name == OmitField || used[name] {
+			return
+		}
+		used[name] = true
+		fields = append(fields, envelopeField{name: name, value: value})
+	}
+
+	add(s.StatusField, nr.status)
+	add(s.SuccessField, nr.success)
+	add(s.MessageField, nr.message)
+	if nr.data != nil {
+		add(s.DataField, nr.data)
+	}
+	if len(nr.meta) > 0 {
+		add(s.MetaField, nr.meta)
+	}
+	if nr.requestID != "" {
+		add(s.RequestIDField, nr.requestID)
+	}
+	add(s.TimestampField, nr.timestamp)
+
+	for _, name := range slices.Sorted(maps.Keys(s.Static)) {
+		add(name, s.Static[name])
+	}
+
+	return fields
+}
+
+// marshalFields encodes the fields as a JSON object, preserving their order
+func marshalFields(fields []envelopeField) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvelopeSchema(t *testing.T) {
+	schema := EnvelopeSchema{
+		StatusField:    OmitField,
+		SuccessField:   "ok",
+		MessageField:   "error",
+		DataField:      "result",
+		TimestampField: OmitField,
+		Static:         map[string]any{"api_version": "v2"},
+	}
+
+	w := httptest.NewRecorder()
+	NewOK(w,
+		WithEnvelopeSchema(schema),
+		WithData(map[string]string{"id": "1"}),
+		Send(),
+	)
+
+	expected := `{"ok":true,"error":"Success","result":{"id":"1"},"api_version":"v2"}` + "\n"
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
+	}
+
+	t.Run("ExtractWithSchema", func(t *testing.T) {
+		val, err := ExtractResponseBody[NewResponse](w.Result(), schema)
+		if err != nil {
+			t.Fatalf("Expected no error on ExtractResponseBody(), got %v", err)
+		}
+
+		if !val.Success() || val.Message() != "Success" {
+			t.Errorf("Expected ok=true and error='Success', got %v and '%s'", val.Success(), val.Message())
+		}
+
+		dataMap, ok := val.Data().(map[string]any)
+		if !ok || dataMap["id"] != "1" {
+			t.Errorf("Expected result map with id '1', got '%v'", val.Data())
+		}
+	})
+}
+
+func TestGlobalEnvelopeSchema(t *testing.T) {
+	SetEnvelopeSchema(EnvelopeSchema{SuccessField: "ok", TimestampField: OmitField})
+	defer SetEnvelopeSchema(DefaultEnvelopeSchema())
+
+	w := httptest.NewRecorder()
+	NewErr(w, WithStatus(http.StatusConflict), Send())
+
+	var raw map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if _, exists := raw["timestamp"]; exists {
+		t.Errorf("Expected timestamp to be omitted, got %v", raw)
+	}
+
+	if raw["ok"] != false || raw["status"] != float64(http.StatusConflict) {
+		t.Errorf("Expected renamed success field and default status field, got %v", raw)
+	}
+
+	var response NewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Status() != http.StatusConflict || response.Success() {
+		t.Errorf("Expected decoding to honour the global schema, got %+v", response)
+	}
+}
+
+func TestEnvelopeSchemaStaticCannotShadow(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewOK(w,
+		WithEnvelopeSchema(EnvelopeSchema{Static: map[string]any{"status": "shadowed"}}),
+		Send(),
+	)
+
+	var response NewResponse
+	if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Status() != http.StatusOK {
+		t.Errorf("Expected static fields not to shadow the status, got %d", response.Status())
+	}
+}
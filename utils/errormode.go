@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrorMode controls how much of an error attached with WithError is sent to the client
+type ErrorMode int32
+
+const (
+	// ErrorModeProduction sends only a reference ID, the cause is logged under the same ID
+	ErrorModeProduction ErrorMode = iota
+	// ErrorModeDevelopment also sends the error chain, the caller and the stack trace
+	ErrorModeDevelopment
+)
+
+// ErrorMetaField is the meta field holding the details of an attached error
+const ErrorMetaField = "error"
+
+// maxStackDepth limits the number of frames captured by WithError
+const maxStackDepth = 32
+
+// modulePath prefixes the functions of gecho, which are left out of captured stacks
+const modulePath = "github.com/MonkyMars/gecho"
+
+// errorMode holds the global error mode, shared by all responses
+var errorMode atomic.Int32
+
+// SetErrorMode sets the global mode used for errors attached with WithError
+// Production is the default, development must never be enabled on a public server
+func SetErrorMode(mode ErrorMode) {
+	errorMode.Store(int32(mode))
+}
+
+// GetErrorMode returns the global mode used for errors attached with WithError
+func GetErrorMode() ErrorMode {
+	return ErrorMode(errorMode.Load())
+}
+
+// errorOptions holds an attached error and how it is reported
+type errorOptions struct {
+	err    error
+	stack  []runtime.Frame
+	mode   *ErrorMode
+	logger *Logger
+}
+
+// WithError attaches the cause of an error response
+// It is logged with a reference ID that is sent in the "error" meta field, in development mode
+// the meta field also holds the error chain, the caller and the stack trace where WithError was called
+// Example: gecho.InternalServerError(w, gecho.WithError(err), gecho.Send())
+func WithError(err error) ResponseOption {
+	if err == nil {
+		return func(rc *responseConfig) {}
+	}
+
+	stack := captureStack()
+	return func(rc *responseConfig) {
+		rc.errorDetail.err = err
+		rc.errorDetail.stack = stack
+	}
+}
+
+// WithErrorMode overrides the global error mode for a single response
+func WithErrorMode(mode ErrorMode) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.errorDetail.mode = &mode
+	}
+}
+
+// WithErrorLogger sets the logger for an attached error, defaults to the logger of the request context
+func WithErrorLogger(logger *Logger) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.errorDetail.logger = logger
+	}
+}
+
+// captureStack returns the stack of the caller of its caller, without the frames of gecho
+// Frames of gecho are only skipped at the top, so the caller is the first frame outside of it
+func captureStack() []runtime.Frame {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		if !(len(stack) == 0 && internalFrame(frame)) && !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, frame)
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// internalFrame reports whether a frame belongs to gecho itself, its tests excluded
+func internalFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	return strings.HasPrefix(frame.Function, modulePath+".") || strings.HasPrefix(frame.Function, modulePath+"/")
+}
+
+// newErrorID returns a random reference ID for an attached error
+func newErrorID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// errorChain flattens err and everything it wraps, including the members of errors.Join
+func errorChain(err error) []map[string]string {
+	var chain []map[string]string
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, map[string]string{
+			"type":    fmt.Sprintf("%T", err),
+			"message": err.Error(),
+		})
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, member := range e.Unwrap() {
+				walk(member)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// formatFrame renders a frame as "function file:line"
+func formatFrame(frame runtime.Frame) string {
+	return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+}
+
+// reportError logs the attached error under a new reference ID and adds its details to the meta block
+func (r *Response) reportError() {
+	detail := r.errorDetail
+	if detail.err == nil {
+		return
+	}
+
+	mode := GetErrorMode()
+	if detail.mode != nil {
+		mode = *detail.mode
+	}
+	logger := detail.logger
+	switch {
+	case r.req == nil && logger == nil:
+		logger = DefaultLogger()
+	case logger == nil:
+		logger = LoggerFrom(r.req.Context())
+	case r.req != nil:
+		logger = logger.WithContext(r.req.Context())
+	}
+
+	id := newErrorID()
+	caller := ""
+	if len(detail.stack) > 0 {
+		caller = fmt.Sprintf("%s:%d", detail.stack[0].File, detail.stack[0].Line)
+	}
+
+	fields := []any{
+		Field("error_id", id),
+		Field("status", r.status),
+		Field("error", detail.err.Error()),
+	}
+	if r.req != nil {
+		fields = append(fields, Field("method", r.req.Method), Field("path", r.req.URL.Path))
+	}
+	if caller != "" {
+		fields = append(fields, Field("caller", caller))
+	}
+	if r.status >= 500 {
+		logger.Error(append([]any{"Request failed"}, fields...)...)
+	} else {
+		logger.Warn(append([]any{"Request rejected"}, fields...)...)
+	}
+
+	info := map[string]any{"id": id}
+	if mode == ErrorModeDevelopment {
+		stack := make([]string, len(detail.stack))
+		for i, frame := range detail.stack {
+			stack[i] = formatFrame(frame)
+		}
+		info["message"] = detail.err.Error()
+		info["chain"] = errorChain(detail.err)
+		info["caller"] = caller
+		info["stack"] = stack
+	}
+
+	if r.meta == nil {
+		r.meta = make(map[string]any)
+	}
+	r.meta[ErrorMetaField] = info
+}
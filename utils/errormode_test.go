@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// errorMeta sends an error response with err attached and returns its error meta field and the log output
+func errorMeta(t *testing.T, err error, opts ...ResponseOption) (map[string]any, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithErrorOutput(&buf), WithLogFormat(FormatJSON)))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	allOpts := append([]ResponseOption{WithRequest(r), WithError(err), WithErrorLogger(logger)}, opts...)
+	if sendErr := NewErr(w, allOpts...).Send(); sendErr != nil {
+		t.Fatalf("Expected no error on Send(), got %v", sendErr)
+	}
+
+	var body struct {
+		Message string         `json:"message"`
+		Meta    map[string]any `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Message != InternalServerErrorMessage {
+		t.Errorf("Expected the generic message, got %q", body.Message)
+	}
+
+	info, ok := body.Meta[ErrorMetaField].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected an error meta field, got %s", w.Body.String())
+	}
+	return info, buf.String()
+}
+
+func TestWithErrorProduction(t *testing.T) {
+	cause := errors.New("pq: connection refused")
+	info, logged := errorMeta(t, cause)
+
+	id, _ := info["id"].(string)
+	if len(id) != 16 {
+		t.Errorf("Expected a 16 character reference ID, got %q", id)
+	}
+	if len(info) != 1 {
+		t.Errorf("Expected only the reference ID in production, got %v", info)
+	}
+
+	if !strings.Contains(logged, id) || !strings.Contains(logged, cause.Error()) {
+		t.Errorf("Expected the ID and cause to be logged, got %s", logged)
+	}
+	if !strings.Contains(logged, "/orders/7") || !strings.Contains(logged, "errormode_test.go") {
+		t.Errorf("Expected the path and caller to be logged, got %s", logged)
+	}
+}
+
+func TestWithErrorDevelopment(t *testing.T) {
+	inner := errors.New("disk full")
+	cause := fmt.Errorf("saving order: %w", errors.Join(inner, errors.New("index locked")))
+
+	info, _ := errorMeta(t, cause, WithErrorMode(ErrorModeDevelopment))
+
+	if info["message"] != cause.Error() {
+		t.Errorf("Expected the error message, got %v", info["message"])
+	}
+
+	chain, _ := info["chain"].([]any)
+	var messages []string
+	for _, link := range chain {
+		messages = append(messages, link.(map[string]any)["message"].(string))
+	}
+	if len(messages) != 4 || messages[2] != "disk full" || messages[3] != "index locked" {
+		t.Errorf("Expected the chain with the joined errors, got %v", messages)
+	}
+
+	if caller, _ := info["caller"].(string); !strings.Contains(caller, "errormode_test.go") {
+		t.Errorf("Expected the caller in the test file, got %q", caller)
+	}
+	stack, _ := info["stack"].([]any)
+	if len(stack) == 0 || !strings.Contains(stack[0].(string), "errorMeta") {
+		t.Errorf("Expected the stack to start at the caller, got %v", stack)
+	}
+}
+
+func TestErrorModeGlobal(t *testing.T) {
+	SetErrorMode(ErrorModeDevelopment)
+	defer SetErrorMode(ErrorModeProduction)
+
+	info, _ := errorMeta(t, errors.New("boom"))
+	if _, ok := info["stack"]; !ok {
+		t.Errorf("Expected development details from the global mode, got %v", info)
+	}
+
+	info, _ = errorMeta(t, errors.New("boom"), WithErrorMode(ErrorModeProduction))
+	if len(info) != 1 {
+		t.Errorf("Expected the option to override the global mode, got %v", info)
+	}
+}
+
+func TestWithErrorNil(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewErr(w, WithError(nil), Send())
+
+	if strings.Contains(w.Body.String(), `"meta"`) {
+		t.Errorf("Expected no error meta for a nil error, got %s", w.Body.String())
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetDefaultLogger(NewLogger(NewConfig(WithOutput(&buf), WithErrorOutput(&buf))))
+	defer SetDefaultLogger(nil)
+
+	NewErr(httptest.NewRecorder(), WithError(errors.New("cache miss storm")), Send())
+	if !strings.Contains(buf.String(), "cache miss storm") {
+		t.Errorf("Expected the default logger to be used, got %s", buf.String())
+	}
+
+	SetDefaultLogger(nil)
+	if DefaultLogger() == nil {
+		t.Error("Expected a built-in default logger after reset")
+	}
+}
@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Formatter writes log entries, set it with WithFormatter or on a Sink to use a custom format
+// Format writes a single entry to buf, a missing trailing newline is added by the logger
+// When it returns an error the entry is written in the text format with a format_error field instead
+// Example: type logfmt struct{}; func (logfmt) Format(buf *bytes.Buffer, e *utils.Entry) error { ... }
+type Formatter interface {
+	Format(buf *bytes.Buffer, e *Entry) error
+}
+
+// FormatterFor returns the built-in formatter of the format, colorizing it when supported
+func FormatterFor(format Format, colorize bool) Formatter {
+	switch format {
+	case FormatJSON:
+		return JSONFormatter{}
+	case FormatPretty:
+		return PrettyFormatter{Colorize: colorize}
+	default:
+		return TextFormatter{Colorize: colorize}
+	}
+}
+
+// formatEntry returns the entry written by the formatter, ending with a newline
+func formatEntry(formatter Formatter, e *Entry) []byte {
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, e); err != nil {
+		// Keep the entry rather than losing it with its formatter
+		fallback := *e
+		fallback.Fields = append(slices.Clone(e.Fields), FieldPair{Key: "format_error", Value: err.Error()})
+		buf.Reset()
+		TextFormatter{}.Format(&buf, &fallback)
+	}
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+var levelColors = map[Level]string{
+	LevelDebug: "\033[36m", // Cyan
+	LevelInfo:  "\033[32m", // Green
+	LevelWarn:  "\033[33m", // Yellow
+	LevelError: "\033[31m", // Red
+	LevelFatal: "\033[35m", // Magenta
+}
+
+const colorReset = "\033[0m"
+
+// TextFormatter writes entries as plain text with the fields in braces, the format of FormatText
+type TextFormatter struct {
+	Colorize bool // Colors the level
+}
+
+// Format writes the entry in human-readable text format
+func (f TextFormatter) Format(buf *bytes.Buffer, e *Entry) error {
+	level, colorize := e.Level, f.Colorize
+	var sb strings.Builder
+
+	// Timestamp
+	sb.WriteString(e.Timestamp)
+	sb.WriteString(" ")
+
+	// Level with optional color
+	if colorize {
+		sb.WriteString(levelColors[level])
+	}
+	sb.WriteString(fmt.Sprintf("%-5s", level))
+	if colorize {
+		sb.WriteString(colorReset)
+	}
+	sb.WriteString(" ")
+
+	// Caller
+	if e.Caller != "" {
+		sb.WriteString("[")
+		sb.WriteString(e.Caller)
+		sb.WriteString("] ")
+	}
+
+	// Logger name
+	if e.Logger != "" {
+		sb.WriteString(e.Logger)
+		sb.WriteString(": ")
+	}
+
+	// Message
+	sb.WriteString(e.Message)
+
+	// Fields
+	if len(e.Fields) > 0 {
+		sb.WriteString(" {")
+		for i, f := range e.Fields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(f.Key)
+			sb.WriteString("=")
+			sb.WriteString(fmt.Sprint(f.Value))
+		}
+		sb.WriteString("}")
+	}
+
+	buf.WriteString(sb.String())
+	buf.WriteByte('\n')
+	return nil
+}
+
+// JSONFormatter writes entries as JSON objects, one per line, the format of FormatJSON
+type JSONFormatter struct{}
+
+// Format writes the entry in JSON format
+func (JSONFormatter) Format(buf *bytes.Buffer, e *Entry) error {
+	// Convert fields to map for JSON output
+	fieldsMap := make(map[string]any)
+	for _, f := range e.Fields {
+		fieldsMap[f.Key] = f.Value
+	}
+
+	jsonEntry := struct {
+		Timestamp string         `json:"timestamp"`
+		Level     string         `json:"level"`
+		Logger    string         `json:"logger,omitempty"`
+		Message   string         `json:"message"`
+		Caller    string         `json:"caller,omitempty"`
+		Fields    map[string]any `json:"fields,omitempty"`
+	}{
+		Timestamp: e.Timestamp,
+		Level:     e.Level.String(),
+		Logger:    e.Logger,
+		Message:   e.Message,
+		Caller:    e.Caller,
+		Fields:    fieldsMap,
+	}
+
+	data, err := json.Marshal(jsonEntry)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// PrettyFormatter writes entries with the fields in parentheses and the caller last, the format of FormatPretty
+type PrettyFormatter struct {
+	Colorize   bool   // Colors the timestamp, level, name, fields and caller
+	TimeFormat string // Layout of the time of the entry, DefaultPrettyTimeFormat when empty
+}
+
+// DefaultPrettyTimeFormat is the layout of the time written by PrettyFormatter, the time of day in milliseconds
+const DefaultPrettyTimeFormat = "15:04:05.000"
+
+// Format writes the entry in pretty format with parentheses around key-value pairs
+func (f PrettyFormatter) Format(buf *bytes.Buffer, e *Entry) error {
+	level, colorize := e.Level, f.Colorize
+	var sb strings.Builder
+
+	levelColor := levelColors[level]
+
+	// Helper function to write with optional color
+	writeColored := func(color, text string) {
+		if colorize {
+			sb.WriteString(color)
+		}
+		sb.WriteString(text)
+		if colorize {
+			sb.WriteString(colorReset)
+		}
+	}
+
+	// Time of the entry, or its formatted timestamp for entries built without one
+	timestamp := e.Timestamp
+	if !e.Time.IsZero() {
+		layout := f.TimeFormat
+		if layout == "" {
+			layout = DefaultPrettyTimeFormat
+		}
+		timestamp = e.Time.Format(layout)
+	}
+	writeColored("\033[90m", timestamp) // Gray
+	sb.WriteString("  ")
+
+	// Level with optional color
+	writeColored(levelColor, fmt.Sprintf("%-5s", level))
+	sb.WriteString("  ")
+
+	// Logger name
+	if e.Logger != "" {
+		writeColored("\033[1m", e.Logger) // Bold
+		sb.WriteString("  ")
+	}
+
+	// Message
+	if e.Message != "" {
+		sb.WriteString(e.Message)
+	}
+
+	// Fields in parentheses format (preserves order)
+	if len(e.Fields) > 0 {
+		if e.Message != "" {
+			sb.WriteString(" ")
+		}
+		for _, f := range e.Fields {
+			if colorize {
+				sb.WriteString(levelColor)
+			}
+			sb.WriteString("(")
+			if colorize {
+				sb.WriteString(colorReset)
+			}
+			sb.WriteString(f.Key)
+			if colorize {
+				sb.WriteString("\033[94m") // Light blue
+			}
+			sb.WriteString("=")
+			if colorize {
+				sb.WriteString(colorReset)
+			}
+			sb.WriteString(fmt.Sprint(f.Value))
+			if colorize {
+				sb.WriteString(levelColor)
+			}
+			sb.WriteString(") ")
+			if colorize {
+				sb.WriteString(colorReset)
+			}
+		}
+	}
+
+	// Caller at the end if present
+	if e.Caller != "" {
+		sb.WriteString(" ")
+		writeColored("\033[38;5;208m", fmt.Sprintf("[%s]", e.Caller)) // Orange
+	}
+
+	buf.WriteString(strings.TrimRight(sb.String(), " "))
+	buf.WriteByte('\n')
+	return nil
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logfmtFormatter writes entries as key=value pairs, without a trailing newline
+type logfmtFormatter struct{}
+
+func (logfmtFormatter) Format(buf *bytes.Buffer, e *Entry) error {
+	fmt.Fprintf(buf, "level=%s logger=%s msg=%q", strings.ToLower(e.Level.String()), e.Logger, e.Message)
+	for _, f := range e.Fields {
+		fmt.Fprintf(buf, " %s=%v", f.Key, f.Value)
+	}
+	return nil
+}
+
+// failingFormatter fails for every entry
+type failingFormatter struct{}
+
+func (failingFormatter) Format(buf *bytes.Buffer, e *Entry) error {
+	buf.WriteString("partial")
+	return errors.New("unsupported value")
+}
+
+func TestWithFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithFormatter(logfmtFormatter{}))).Named("api")
+
+	logger.Info("Started", Field("port", 8080))
+	logger.Warn("Slow")
+
+	expected := "level=info logger=api msg=\"Started\" port=8080\nlevel=warn logger=api msg=\"Slow\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetFormat(FormatJSON)
+	logger.Info("Switched")
+	if !strings.HasPrefix(buf.String(), "{") {
+		t.Errorf("Expected SetFormat to replace the formatter, got %q", buf.String())
+	}
+}
+
+func TestFormatterError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithFormatter(failingFormatter{}), WithShowCaller(false)))
+
+	logger.Info("Kept", Field("id", 7))
+	if strings.Contains(buf.String(), "partial") || !strings.Contains(buf.String(), "INFO  Kept {id=7, format_error=unsupported value}") {
+		t.Errorf("Expected the entry in the text format, got %q", buf.String())
+	}
+
+	buf.Reset()
+	NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatJSON))).Info("Channel", Field("ch", make(chan int)))
+	if !strings.Contains(buf.String(), "Channel {ch=") || !strings.Contains(buf.String(), "format_error=json") {
+		t.Errorf("Expected a JSON encoding error to keep the entry, got %q", buf.String())
+	}
+}
+
+func TestSinkFormatter(t *testing.T) {
+	var custom, builtin bytes.Buffer
+	logger := NewLogger(NewConfig(WithSinks(
+		Sink{Output: &custom, Formatter: logfmtFormatter{}},
+		Sink{Output: &builtin, Format: FormatJSON},
+	)))
+
+	logger.Info("Fanned out")
+	if custom.String() != "level=info logger= msg=\"Fanned out\"\n" {
+		t.Errorf("Expected the custom format, got %q", custom.String())
+	}
+	if !strings.Contains(builtin.String(), `"message":"Fanned out"`) {
+		t.Errorf("Expected the built-in format, got %q", builtin.String())
+	}
+}
+
+func TestPrettyTimeFormat(t *testing.T) {
+	// Time formats whose time of day is shorter than the default layout
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", time.Kitchen, time.RFC3339} {
+		var buf bytes.Buffer
+		logger := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatPretty), WithColorize(false), WithShowCaller(false), WithTimeFormat(layout)))
+		logger.Info("Started")
+
+		fields := strings.Fields(buf.String())
+		if len(fields) < 3 || len(fields[0]) != len(DefaultPrettyTimeFormat) || fields[2] != "Started" {
+			t.Errorf("Expected the time of day with layout %q, got %q", layout, buf.String())
+		}
+	}
+
+	e := &Entry{Time: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), Level: LevelInfo, Message: "Started"}
+	var buf bytes.Buffer
+	PrettyFormatter{TimeFormat: time.Kitchen}.Format(&buf, e)
+	if buf.String() != "10:30AM  INFO   Started\n" {
+		t.Errorf("Expected the layout of the formatter, got %q", buf.String())
+	}
+}
+
+func TestFormatterFor(t *testing.T) {
+	e := &Entry{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Timestamp: "2024-05-01 10:00:00.000", Level: LevelError, Message: "Failed", Fields: []FieldPair{{Key: "code", Value: 3}}}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{FormatText, "2024-05-01 10:00:00.000 ERROR Failed {code=3}\n"},
+		{FormatJSON, `{"timestamp":"2024-05-01 10:00:00.000","level":"ERROR","message":"Failed","fields":{"code":3}}` + "\n"},
+		{FormatPretty, "10:00:00.000  ERROR  Failed (code=3)\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := FormatterFor(tt.format, false).Format(&buf, e); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelRulesEnv is the environment variable holding the level rules applied by WithEnvLevels, e.g. "info,db.*=debug,http=warn"
+const LevelRulesEnv = "GECHO_LOG_LEVELS"
+
+// MarshalText encodes the level as its lowercase name, such as "debug"
+func (l Level) MarshalText() ([]byte, error) {
+	if l < LevelDebug || l > LevelFatal {
+		return nil, fmt.Errorf("gecho: unknown log level %d", int(l))
+	}
+	return []byte(strings.ToLower(l.String())), nil
+}
+
+// UnmarshalText decodes a level name, unlike ParseLevel it rejects unknown names
+func (l *Level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug", "info", "warn", "warning", "error", "fatal":
+		*l = ParseLevel(string(text))
+		return nil
+	}
+	return fmt.Errorf("gecho: unknown log level %q", text)
+}
+
+// LevelVar is a log level that can be changed at runtime and is shared by the loggers derived from one another
+// Overrides set the level of the loggers with a given name, see Logger.Named
+// An override named "db" applies to the db logger only, "db.*" to db and every logger below it such as "db.pool"
+// The most specific override wins
+// Example: levels := utils.NewLevelVar(utils.LevelInfo); levels.SetOverride("db.*", utils.LevelDebug)
+type LevelVar struct {
+	level     atomic.Int64
+	mu        sync.Mutex                 // Serializes changes to the overrides
+	overrides atomic.Pointer[levelRules] // Replaced on every change, so reads need no lock
+}
+
+// levelRules is a snapshot of the overrides with the levels resolved for each name so far
+type levelRules struct {
+	patterns map[string]Level
+	resolved sync.Map // Logger name to overrideLevel
+}
+
+// overrideLevel is the resolved override of a name
+type overrideLevel struct {
+	level Level
+	ok    bool
+}
+
+// resolve returns the override of the most specific pattern matching name
+func (r *levelRules) resolve(name string) (Level, bool) {
+	if cached, ok := r.resolved.Load(name); ok {
+		o := cached.(overrideLevel)
+		return o.level, o.ok
+	}
+
+	o := overrideLevel{}
+	if level, ok := r.patterns[name]; ok {
+		o = overrideLevel{level: level, ok: true}
+	} else {
+		for prefix := name; prefix != ""; {
+			if level, ok := r.patterns[prefix+".*"]; ok {
+				o = overrideLevel{level: level, ok: true}
+				break
+			}
+			i := strings.LastIndexByte(prefix, '.')
+			if i < 0 {
+				break
+			}
+			prefix = prefix[:i]
+		}
+	}
+
+	r.resolved.Store(name, o)
+	return o.level, o.ok
+}
+
+// NewLevelVar creates a level variable set to level
+func NewLevelVar(level Level) *LevelVar {
+	lv := &LevelVar{}
+	lv.Set(level)
+	return lv
+}
+
+// Level returns the level of loggers without an override
+func (lv *LevelVar) Level() Level {
+	return Level(lv.level.Load())
+}
+
+// Set changes the level of loggers without an override
+func (lv *LevelVar) Set(level Level) {
+	lv.level.Store(int64(level))
+}
+
+// LevelFor returns the level of the logger with the name, its override or the shared level
+func (lv *LevelVar) LevelFor(name string) Level {
+	if name != "" {
+		if rules := lv.overrides.Load(); rules != nil {
+			if level, ok := rules.resolve(name); ok {
+				return level
+			}
+		}
+	}
+	return lv.Level()
+}
+
+// SetOverride sets the level of the loggers matching the pattern, such as LevelDebug for a single subsystem
+// Patterns rejected by ValidateLevelPattern never match a logger
+func (lv *LevelVar) SetOverride(pattern string, level Level) {
+	lv.updateOverrides(func(overrides map[string]Level) {
+		overrides[pattern] = level
+	})
+}
+
+// RemoveOverride makes the loggers matching the pattern use the shared level again
+func (lv *LevelVar) RemoveOverride(pattern string) {
+	lv.updateOverrides(func(overrides map[string]Level) {
+		delete(overrides, pattern)
+	})
+}
+
+// Overrides returns a copy of the levels set per logger name pattern
+func (lv *LevelVar) Overrides() map[string]Level {
+	overrides := make(map[string]Level)
+	if current := lv.overrides.Load(); current != nil {
+		maps.Copy(overrides, current.patterns)
+	}
+	return overrides
+}
+
+// SetRules applies comma separated level rules, such as "info,db.*=debug,http=warn"
+// A bare level, or one for the pattern "*", sets the shared level, the others are added as overrides
+// Nothing is changed when a rule is invalid
+func (lv *LevelVar) SetRules(rules string) error {
+	level, overrides, err := ParseLevelRules(rules)
+	if err != nil {
+		return err
+	}
+	if level != nil {
+		lv.Set(*level)
+	}
+	if len(overrides) > 0 {
+		lv.updateOverrides(func(current map[string]Level) {
+			maps.Copy(current, overrides)
+		})
+	}
+	return nil
+}
+
+// ParseLevelRules parses comma separated level rules, such as "info,db.*=debug,http=warn"
+// It returns the level of a bare rule or of the pattern "*", nil without one, and the overrides by pattern
+func ParseLevelRules(rules string) (*Level, map[string]Level, error) {
+	var shared *Level
+	overrides := make(map[string]Level)
+
+	for rule := range strings.SplitSeq(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		pattern, levelName, found := strings.Cut(rule, "=")
+		if !found {
+			pattern, levelName = "*", rule
+		}
+		pattern = strings.TrimSpace(pattern)
+
+		var level Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(levelName))); err != nil {
+			return nil, nil, fmt.Errorf("gecho: level rule %q: %w", rule, err)
+		}
+		if pattern != "*" {
+			if err := ValidateLevelPattern(pattern); err != nil {
+				return nil, nil, fmt.Errorf("gecho: level rule %q: %w", rule, err)
+			}
+		}
+
+		if pattern == "*" {
+			shared = &level
+		} else {
+			overrides[pattern] = level
+		}
+	}
+
+	return shared, overrides, nil
+}
+
+// ValidateLevelPattern reports whether the pattern can match a logger name, see LevelVar
+// Patterns are non-empty names, optionally ending with ".*", without any other "*"
+func ValidateLevelPattern(pattern string) error {
+	if pattern == "" || strings.Contains(strings.TrimSuffix(pattern, ".*"), "*") || pattern == ".*" {
+		return fmt.Errorf("gecho: invalid logger name pattern %q", pattern)
+	}
+	return nil
+}
+
+// updateOverrides applies change to a copy of the overrides and stores it
+func (lv *LevelVar) updateOverrides(change func(map[string]Level)) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	overrides := lv.Overrides()
+	change(overrides)
+	lv.overrides.Store(&levelRules{patterns: overrides})
+}
+
+// String returns the name of the shared level
+func (lv *LevelVar) String() string {
+	return lv.Level().String()
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLevelVarShared(t *testing.T) {
+	var buf bytes.Buffer
+	parent := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatText), WithShowCaller(false)))
+	child := parent.WithField("component", "billing")
+
+	child.Debug("hidden")
+	parent.SetLevel(LevelDebug)
+	child.Debug("shown")
+
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("Expected the child to follow the level of its parent, got %q", buf.String())
+	}
+	if child.LevelVar() != parent.LevelVar() {
+		t.Error("Expected the child to share the level of its parent")
+	}
+}
+
+func TestLevelVarOverrides(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevelVar(LevelWarn)
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatText), WithShowCaller(false), WithLevelVar(levels)))
+
+	db := logger.Named("db")
+	pool := db.Named("pool")
+	levels.SetOverride("db", LevelDebug)
+
+	logger.Info("root info")
+	db.Debug("db debug")
+	pool.Debug("pool debug")
+
+	out := buf.String()
+	if strings.Contains(out, "root info") || !strings.Contains(out, "db debug") || strings.Contains(out, "pool debug") {
+		t.Errorf("Expected only the db override to apply, got %q", out)
+	}
+	if pool.Name() != "db.pool" {
+		t.Errorf("Expected the nested name db.pool, got %q", pool.Name())
+	}
+
+	levels.RemoveOverride("db")
+	if db.Level() != LevelWarn || len(levels.Overrides()) != 0 {
+		t.Errorf("Expected the shared level after removing the override, got %v", db.Level())
+	}
+}
+
+func TestLevelVarConcurrent(t *testing.T) {
+	levels := NewLevelVar(LevelInfo)
+	logger := NewLogger(NewConfig(WithOutput(&bytes.Buffer{}), WithLevelVar(levels))).Named("worker")
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				levels.Set(Level(i % 4))
+				levels.SetOverride("worker", LevelError)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				logger.Level()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLevelText(t *testing.T) {
+	data, err := json.Marshal(map[string]Level{"db": LevelDebug})
+	if err != nil || string(data) != `{"db":"debug"}` {
+		t.Errorf("Expected lowercase level names, got %s (%v)", data, err)
+	}
+
+	var level Level
+	if err := json.Unmarshal([]byte(`"WARNING"`), &level); err != nil || level != LevelWarn {
+		t.Errorf("Expected LevelWarn, got %v (%v)", level, err)
+	}
+	if err := json.Unmarshal([]byte(`"verbose"`), &level); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+}
+
+func TestLevelRules(t *testing.T) {
+	levels := NewLevelVar(LevelInfo)
+	if err := levels.SetRules("warn, db.*=debug, db.pool=error, http=debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		level Level
+	}{
+		{"", LevelWarn},
+		{"cache", LevelWarn},
+		{"db", LevelDebug},
+		{"db.query", LevelDebug},
+		{"db.pool", LevelError},
+		{"db.pool.conn", LevelDebug},
+		{"http", LevelDebug},
+		{"http.client", LevelWarn},
+		{"dbx", LevelWarn},
+	}
+	for _, tt := range tests {
+		if got := levels.LevelFor(tt.name); got != tt.level {
+			t.Errorf("LevelFor(%q) = %v, expected %v", tt.name, got, tt.level)
+		}
+	}
+
+	// Resolved levels follow later changes
+	levels.SetOverride("http.*", LevelError)
+	if got := levels.LevelFor("http.client"); got != LevelError {
+		t.Errorf("Expected the new override, got %v", got)
+	}
+}
+
+func TestParseLevelRulesErrors(t *testing.T) {
+	for _, rules := range []string{"verbose", "db=loud", "=debug", "db*=debug", "*.db=info"} {
+		if _, _, err := ParseLevelRules(rules); err == nil {
+			t.Errorf("Expected %q to be rejected", rules)
+		}
+	}
+
+	levels := NewLevelVar(LevelInfo)
+	if err := levels.SetRules("debug,db=loud"); err == nil || levels.Level() != LevelInfo {
+		t.Errorf("Expected invalid rules to change nothing, got %v", levels.Level())
+	}
+}
+
+func TestLevelRulesEnv(t *testing.T) {
+	t.Setenv(LevelRulesEnv, "error,jobs.*=debug")
+
+	var buf bytes.Buffer
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatText), WithShowCaller(false), WithEnvLevels()))
+	logger.Info("hidden")
+	logger.Named("jobs").Named("mailer").Debug("shown")
+
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "jobs.mailer: shown") {
+		t.Errorf("Expected the rules of the environment, got %q", buf.String())
+	}
+
+	// The environment is only read on request, however the level is set
+	levels := []struct {
+		name   string
+		config Config
+	}{
+		{"WithLogLevel", NewConfig(WithLogLevel(LevelDebug))},
+		{"ConfigLiteral", Config{Level: LevelDebug}},
+		{"AssignedLevel", func() Config {
+			config := DefaultConfig()
+			config.Level = LevelDebug
+			return config
+		}()},
+	}
+	for _, tt := range levels {
+		buf.Reset()
+		tt.config.Output, tt.config.Format, tt.config.ShowCaller = &buf, FormatText, false
+		logger := NewLogger(tt.config)
+		logger.Debug("debug")
+		logger.Named("jobs").Debug("jobs")
+		if !strings.Contains(buf.String(), "debug") || !strings.Contains(buf.String(), "jobs") {
+			t.Errorf("%s: Expected the configured level without the environment, got %q", tt.name, buf.String())
+		}
+	}
+
+	// Slog handlers do their own filtering
+	buf.Reset()
+	slogger := NewLoggerFromSlog(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	slogger.Info("handled")
+	slogger.Named("jobs").Debug("filtered")
+	if !strings.Contains(buf.String(), "handled") || strings.Contains(buf.String(), "filtered") {
+		t.Errorf("Expected the level of the slog handler, got %q", buf.String())
+	}
+
+	buf.Reset()
+	NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatText), WithLevelRules("db=noisy")))
+	if !strings.Contains(buf.String(), "Invalid log level rules") {
+		t.Errorf("Expected a warning for invalid rules, got %q", buf.String())
+	}
+}
+
+func TestNamedLoggerFormats(t *testing.T) {
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{FormatText, "INFO  db.pool: Connected {size=4}"},
+		{FormatJSON, `"level":"INFO","logger":"db.pool","message":"Connected"`},
+		{FormatPretty, "INFO   db.pool  Connected (size=4)"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(tt.format), WithColorize(false), WithShowCaller(false)))
+		logger.Named("db").Named("pool").Info("Connected", Field("size", 4))
+
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("Expected %q in format %d, got %q", tt.expected, tt.format, buf.String())
+		}
+	}
+}
@@ -1,36 +1,33 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-const NotFoundMessage = "Resource not found"
-const InternalServerErrorMessage = "Internal server error"
-const ServiceUnavailableMessage = "Service unavailable"
-const BadRequestMessage = "Bad request"
-const UnauthorizedMessage = "Unauthorized"
-const ForbiddenMessage = "Forbidden"
-const MethodNotAllowedMessage = "Method not allowed"
-const ConflictMessage = "Conflict"
-const TooManyRequestsMessage = "Too many requests"
+// Messages that do not belong to a single status, see messages.go for the status messages
+const ValidationFailedMessage = "Validation failed"
+const ClientClosedRequestMessage = "Client closed request"
+const MalformedBodyMessage = "Malformed request body"
+const InvalidFieldMessage = "Invalid field value"
+const EmptyBodyMessage = "Request body is empty"
+const InvalidRedirectMessage = "Invalid redirect location"
 
 // NewResponse is a struct that holds the response data for API responses
 type NewResponse struct {
-	status    int       // HTTP status code, read https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Status for more info
-	success   bool      // Indicates whether the request was successful or not
-	message   string    // Can be used for both error and success messages
-	data      any       // Holds the actual data, returned
-	timestamp time.Time // Unix timestamp of when the response was generated
-}
-
-type jsonResp struct {
-	Status    int       `json:"status"`
-	Success   bool      `json:"success"`
-	Message   string    `json:"message"`
-	Data      any       `json:"data,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
+	status    int             // HTTP status code, read https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Status for more info
+	success   bool            // Indicates whether the request was successful or not
+	message   string          // Can be used for both error and success messages
+	data      any             // Holds the actual data, returned
+	meta      map[string]any  // Metadata about the data, such as pagination
+	requestID string          // ID of the request, set by the request ID middleware
+	timestamp time.Time       // Unix timestamp of when the response was generated
+	schema    *EnvelopeSchema // Field names used for encoding and decoding, nil uses the global schema
 }
 
 func (nr *NewResponse) Status() int {
@@ -45,6 +42,14 @@ func (nr *NewResponse) Data() any {
 	return nr.data
 }
 
+func (nr *NewResponse) Meta() map[string]any {
+	return nr.meta
+}
+
+func (nr *NewResponse) RequestID() string {
+	return nr.requestID
+}
+
 func (nr *NewResponse) Timestamp() time.Time {
 	return nr.timestamp
 }
@@ -53,64 +58,190 @@ func (nr *NewResponse) Success() bool {
 	return nr.success
 }
 
+// envelopeSchema returns the schema used by the response
+func (nr *NewResponse) envelopeSchema() EnvelopeSchema {
+	if nr.schema != nil {
+		return *nr.schema
+	}
+	return GetEnvelopeSchema()
+}
+
+// requestIDOf returns the request ID stored in the context of req
+func requestIDOf(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	return RequestIDFrom(req.Context())
+}
+
 // getTimestamp returns the current time
 func getTimestamp() time.Time {
 	return time.Now()
 }
 
 // MarshalJSON implements custom marshaling for NewResponse so we can keep
-// fields unexported but still produce JSON with exported keys.
+// fields unexported but still produce JSON with the keys of the envelope schema.
 func (nr NewResponse) MarshalJSON() ([]byte, error) {
-	jr := jsonResp{
-		Status:    nr.status,
-		Success:   nr.success,
-		Message:   nr.message,
-		Data:      nr.data,
-		Timestamp: nr.timestamp,
+	return marshalFields(nr.envelopeSchema().fields(nr))
+}
+
+// MarshalXML implements custom XML marshaling for NewResponse, using a <response> root element
+func (nr NewResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "response"}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, f := range nr.envelopeSchema().fields(nr) {
+		if err := encodeXMLValue(e, f.name, f.value); err != nil {
+			return err
+		}
 	}
 
-	return json.Marshal(jr)
+	return e.EncodeToken(start.End())
+}
+
+// Map returns the envelope as a generic map keyed by the envelope schema
+func (nr NewResponse) Map() map[string]any {
+	fields := nr.envelopeSchema().fields(nr)
+	doc := make(map[string]any, len(fields))
+	for _, f := range fields {
+		doc[f.name] = f.value
+	}
+	return doc
 }
 
 // UnmarshalJSON implements custom unmarshaling into the unexported fields of NewResponse.
+// Fields are looked up by the names of the envelope schema, missing fields keep their zero value.
 func (nr *NewResponse) UnmarshalJSON(b []byte) error {
-	var jr jsonResp
-	if err := json.Unmarshal(b, &jr); err != nil {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
 
-	nr.status = jr.Status
-	nr.success = jr.Success
-	nr.message = jr.Message
-	nr.data = jr.Data
-	nr.timestamp = jr.Timestamp
+	schema := nr.envelopeSchema()
+	targets := []struct {
+		name   string
+		target any
+	}{
+		{schema.StatusField, &nr.status},
+		{schema.SuccessField, &nr.success},
+		{schema.MessageField, &nr.message},
+		{schema.DataField, &nr.data},
+		{schema.MetaField, &nr.meta},
+		{schema.RequestIDField, &nr.requestID},
+		{schema.TimestampField, &nr.timestamp},
+	}
+
+	for _, t := range targets {
+		value, ok := raw[t.name]
+		if t.name == OmitField || !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, t.target); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-// writeJSON writes a JSON response to the http.ResponseWriter
-func writeJSON(w http.ResponseWriter, status int, success bool, message string, headers map[string]string, data any) error {
+// StatusAllowsBody reports whether a response with the given status may have a body
+// Informational (1xx), 204 No Content and 304 Not Modified responses never have one
+func StatusAllowsBody(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// writeResponse negotiates an encoder from the request's Accept header and writes the document
+// Without a request the default encoder is used, when nothing is acceptable a 406 envelope is sent
+// No body is written for statuses that forbid one, and for HEAD requests only its length is sent
+// The document is encoded before the status is written, so an encoding failure sends a 500 envelope
+// instead of a truncated body and is returned wrapped in ErrEncoding
+func writeResponse(w http.ResponseWriter, req *http.Request, status int, headers http.Header, doc Document) error {
 	if w == nil {
 		panic("http.ResponseWriter is nil")
 	}
 
-	for key, value := range headers {
-		w.Header().Set(key, value)
+	accept := ""
+	if req != nil {
+		accept = req.Header.Get("Accept")
+	}
+
+	mediaType, enc, ok := encoders.negotiate(accept)
+	if !ok {
+		mediaType, enc = encoders.defaultEncoder()
+		status = http.StatusNotAcceptable
+		doc = NewResponse{
+			status:    status,
+			success:   false,
+			message:   NotAcceptableMessage,
+			data:      map[string]any{"supported": RegisteredMediaTypes()},
+			requestID: requestIDOf(req),
+			timestamp: getTimestamp(),
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	contentType := mediaType
+	if _, isProblem := doc.(ProblemDetails); isProblem {
+		contentType = problemMediaType(mediaType)
+	}
+
+	setHeaders(w, headers)
+	if req != nil {
+		addVary(w, "Accept")
+	}
+
+	if !StatusAllowsBody(status) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(status)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	var encodeErr error
+	if err := enc.Encode(&buf, doc); err != nil {
+		// The headers of the response do not apply to the error that replaces it
+		for key := range headers {
+			w.Header().Del(key)
+		}
+		encodeErr = fmt.Errorf("%w: %w", ErrEncoding, err)
+		status = http.StatusInternalServerError
+		contentType = mediaType
+		buf.Reset()
+		if err := enc.Encode(&buf, NewResponse{
+			status:    status,
+			success:   false,
+			message:   InternalServerErrorMessage,
+			requestID: requestIDOf(req),
+			timestamp: getTimestamp(),
+		}); err != nil {
+			buf.Reset()
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	w.WriteHeader(status)
-	return json.NewEncoder(w).Encode(NewResponse{
-		status:    status,
-		success:   success,
-		message:   message,
-		data:      data,
-		timestamp: getTimestamp(),
-	})
+
+	if req != nil && req.Method == http.MethodHead {
+		return encodeErr
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil && encodeErr == nil {
+		return err
+	}
+	return encodeErr
 }
 
-func ExtractResponseBody[T any](resp *http.Response) (T, error) {
+// ExtractResponseBody decodes the response body into T
+// When T is NewResponse, an optional schema overrides the global envelope schema
+func ExtractResponseBody[T any](resp *http.Response, schema ...EnvelopeSchema) (T, error) {
 	var result T
+	if nr, ok := any(&result).(*NewResponse); ok && len(schema) > 0 {
+		s := schema[0].Normalize()
+		nr.schema = &s
+	}
+
 	err := json.NewDecoder(resp.Body).Decode(&result)
 	return result, err
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -39,11 +40,16 @@ func TestExtractResponseBody(t *testing.T) {
 	}
 }
 
-func TestWriteJson(t *testing.T) {
+func TestWriteResponse(t *testing.T) {
 	w := httptest.NewRecorder()
-	err := writeJSON(w, http.StatusTeapot, true, "I'm a teapot", map[string]string{"tea": "yes"}, map[string]any{"tea": "yes"})
+	err := writeResponse(w, nil, http.StatusTeapot, http.Header{"Tea": {"yes"}}, NewResponse{
+		status:  http.StatusTeapot,
+		success: true,
+		message: "I'm a teapot",
+		data:    map[string]any{"tea": "yes"},
+	})
 	if err != nil {
-		t.Errorf("Expected no error on writeJSON(), got %v", err)
+		t.Errorf("Expected no error on writeResponse(), got %v", err)
 	}
 
 	resp := w.Result()
@@ -51,6 +57,14 @@ func TestWriteJson(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusTeapot, resp.StatusCode)
 	}
 
+	if resp.Header.Get("Content-Type") != MediaTypeJSON {
+		t.Errorf("Expected Content-Type '%s', got '%s'", MediaTypeJSON, resp.Header.Get("Content-Type"))
+	}
+
+	if resp.Header.Get("tea") != "yes" {
+		t.Errorf("Expected header tea='yes', got '%s'", resp.Header.Get("tea"))
+	}
+
 	val, err := ExtractResponseBody[NewResponse](resp)
 	if err != nil {
 		t.Errorf("Expected no error on ExtractResponseBody(), got %v", err)
@@ -70,14 +84,69 @@ func TestWriteJson(t *testing.T) {
 	}
 }
 
-func TestWriteJSON_NilWriter(t *testing.T) {
+func TestWriteResponse_NoBody(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusNotModified} {
+		w := httptest.NewRecorder()
+		err := writeResponse(w, nil, status, nil, NewResponse{status: status, success: true, message: "Empty"})
+		if err != nil {
+			t.Errorf("Expected no error on writeResponse(), got %v", err)
+		}
+
+		if w.Code != status {
+			t.Errorf("Expected status code %d, got %d", status, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected no body for status %d, got %s", status, w.Body.String())
+		}
+		if w.Header().Get("Content-Type") != "" {
+			t.Errorf("Expected no Content-Type for status %d, got %s", status, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestWriteResponse_HEAD(t *testing.T) {
+	doc := NewResponse{status: http.StatusOK, success: true, message: "Success", data: "Alice"}
+
+	get := httptest.NewRecorder()
+	writeResponse(get, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, nil, doc)
+
+	head := httptest.NewRecorder()
+	writeResponse(head, httptest.NewRequest(http.MethodHead, "/", nil), http.StatusOK, nil, doc)
+
+	if head.Body.Len() != 0 {
+		t.Errorf("Expected no body for HEAD, got %s", head.Body.String())
+	}
+	if length := head.Header().Get("Content-Length"); length != strconv.Itoa(get.Body.Len()) {
+		t.Errorf("Expected Content-Length %d, got %s", get.Body.Len(), length)
+	}
+	if head.Header().Get("Content-Type") != MediaTypeJSON {
+		t.Errorf("Expected Content-Type %s, got %s", MediaTypeJSON, head.Header().Get("Content-Type"))
+	}
+}
+
+func TestStatusAllowsBody(t *testing.T) {
+	tests := map[int]bool{
+		http.StatusContinue:    false,
+		http.StatusOK:          true,
+		http.StatusNoContent:   false,
+		http.StatusNotModified: false,
+		http.StatusNotFound:    true,
+	}
+	for status, expected := range tests {
+		if got := StatusAllowsBody(status); got != expected {
+			t.Errorf("StatusAllowsBody(%d) = %v, expected %v", status, got, expected)
+		}
+	}
+}
+
+func TestWriteResponse_NilWriter(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
 			t.Errorf("Expected panic when http.ResponseWriter is nil, but did not panic")
 		}
 	}()
 
-	_ = writeJSON(nil, http.StatusOK, true, "This should panic", nil, nil)
+	_ = writeResponse(nil, nil, http.StatusOK, nil, NewResponse{status: http.StatusOK, success: true, message: "This should panic"})
 }
 
 func TestNewResponseBuilder(t *testing.T) {
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ResponseState is the lifecycle state of a Response
+type ResponseState int
+
+const (
+	// StateBuilt is a response that can still be modified and sent
+	StateBuilt ResponseState = iota
+	// StateSent is a response that has been written
+	StateSent
+	// StateFailed is a response whose send failed, see Response.Err
+	StateFailed
+)
+
+// String returns the name of the state
+func (s ResponseState) String() string {
+	switch s {
+	case StateBuilt:
+		return "built"
+	case StateSent:
+		return "sent"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// ErrAlreadySent is returned when a response is sent or modified after it has been sent
+	ErrAlreadySent = errors.New("gecho: response already sent")
+	// ErrHeadersCommitted is returned when the writer has already written its status line
+	ErrHeadersCommitted = errors.New("gecho: headers already written")
+	// ErrEncoding wraps the error of an encoder that could not encode the response
+	ErrEncoding = errors.New("gecho: encoding response")
+)
+
+// HeaderTracker is implemented by response writers that know whether their headers have been written
+// The writers of the gecho middleware implement it, so responses can detect an earlier write
+type HeaderTracker interface {
+	HeaderWritten() bool
+}
+
+// HeadersWritten reports whether w, or a writer it wraps, has already written its headers
+// Writers that do not implement HeaderTracker are assumed not to have written them
+func HeadersWritten(w http.ResponseWriter) bool {
+	for w != nil {
+		if tracker, ok := w.(HeaderTracker); ok {
+			return tracker.HeaderWritten()
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+	return false
+}
+
+// setHeaders replaces the values of every header in headers on w
+func setHeaders(w http.ResponseWriter, headers http.Header) {
+	for key, values := range headers {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+}
+
+// addVary adds value to the Vary header of w, keeping the values already set and skipping duplicates
+func addVary(w http.ResponseWriter, value string) {
+	for _, line := range w.Header().Values("Vary") {
+		for existing := range strings.SplitSeq(line, ",") {
+			existing = strings.TrimSpace(existing)
+			if existing == "*" || strings.EqualFold(existing, value) {
+				return
+			}
+		}
+	}
+	w.Header().Add("Vary", value)
+}
+
+// modifiable reports whether the response can still be changed, recording ErrAlreadySent when it cannot
+func (r *Response) modifiable() bool {
+	if r.state == StateBuilt {
+		return true
+	}
+	if r.err == nil {
+		r.err = ErrAlreadySent
+	}
+	return false
+}
+
+// fail marks the response as failed with err
+func (r *Response) fail(err error) error {
+	r.state = StateFailed
+	r.err = err
+	return err
+}
+
+// State returns the lifecycle state of the response
+func (r *Response) State() ResponseState {
+	if r == nil {
+		return StateSent
+	}
+	return r.state
+}
+
+// Err returns the error of a failed send, or ErrAlreadySent when the response was modified after being sent
+func (r *Response) Err() error {
+	if r == nil {
+		return nil
+	}
+	return r.err
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// trackingRecorder is a ResponseRecorder that implements HeaderTracker
+type trackingRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (tr *trackingRecorder) HeaderWritten() bool {
+	return tr.ResponseRecorder.Code != http.StatusOK || tr.ResponseRecorder.Body.Len() > 0 || tr.Flushed
+}
+
+// wrappedWriter hides the writer it wraps behind Unwrap
+type wrappedWriter struct {
+	http.ResponseWriter
+}
+
+func (ww *wrappedWriter) Unwrap() http.ResponseWriter {
+	return ww.ResponseWriter
+}
+
+func TestSendTwice(t *testing.T) {
+	w := httptest.NewRecorder()
+	resp := NewOK(w, WithData("first"))
+
+	if resp.State() != StateBuilt {
+		t.Errorf("Expected state built, got %s", resp.State())
+	}
+	if err := resp.Send(); err != nil {
+		t.Fatalf("Expected no error on the first Send(), got %v", err)
+	}
+	if resp.State() != StateSent {
+		t.Errorf("Expected state sent, got %s", resp.State())
+	}
+
+	body := w.Body.String()
+	if err := resp.Send(); !errors.Is(err, ErrAlreadySent) {
+		t.Errorf("Expected ErrAlreadySent, got %v", err)
+	}
+	if w.Body.String() != body {
+		t.Errorf("Expected the body to be written once, got %s", w.Body.String())
+	}
+}
+
+func TestModifyAfterSend(t *testing.T) {
+	w := httptest.NewRecorder()
+	resp := NewOK(w)
+	resp.Send()
+
+	resp.SetStatus(http.StatusTeapot).SetMessage("Late").SetHeader("X-Late", "yes").AddData("late", true)
+
+	if w.Code != http.StatusOK || w.Header().Get("X-Late") != "" {
+		t.Errorf("Expected the sent response to be unchanged, got %d with %v", w.Code, w.Header())
+	}
+	if !errors.Is(resp.Err(), ErrAlreadySent) {
+		t.Errorf("Expected ErrAlreadySent from Err(), got %v", resp.Err())
+	}
+}
+
+func TestSendAfterHeadersWritten(t *testing.T) {
+	w := &trackingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w.WriteHeader(http.StatusAccepted)
+
+	resp := NewOK(&wrappedWriter{ResponseWriter: w}, WithData("late"))
+	if err := resp.Send(); !errors.Is(err, ErrHeadersCommitted) {
+		t.Errorf("Expected ErrHeadersCommitted, got %v", err)
+	}
+	if resp.State() != StateFailed {
+		t.Errorf("Expected state failed, got %s", resp.State())
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no body, got %s", w.Body.String())
+	}
+
+	if err := resp.Send(); !errors.Is(err, ErrHeadersCommitted) {
+		t.Errorf("Expected the failure to be returned again, got %v", err)
+	}
+
+	if err := Stream(w, nil, numbers(1, nil)); !errors.Is(err, ErrHeadersCommitted) {
+		t.Errorf("Expected Stream to return ErrHeadersCommitted, got %v", err)
+	}
+}
+
+func TestMultiValuedHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewOK(w, WithHeader("Vary", "Origin")).
+		AddHeader("Vary", "Cookie").
+		AddHeader("X-Trace", "a").
+		SetHeader("X-Trace", "b").
+		Send()
+
+	vary := w.Header().Values("Vary")
+	if len(vary) != 2 || vary[0] != "Origin" || vary[1] != "Cookie" {
+		t.Errorf("Expected both Vary values, got %v", vary)
+	}
+	if trace := w.Header().Values("X-Trace"); len(trace) != 1 || trace[0] != "b" {
+		t.Errorf("Expected SetHeader to replace values, got %v", trace)
+	}
+}
+
+func TestWithCookie(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewOK(w,
+		WithCookie(&http.Cookie{Name: "session", Value: "abc", HttpOnly: true}),
+		WithCookie(&http.Cookie{Name: "theme", Value: "dark"}),
+		WithCookie(&http.Cookie{Name: "bad name", Value: "x"}),
+		Send(),
+	)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 cookies, got %v", w.Header().Values("Set-Cookie"))
+	}
+	if cookies[0].Name != "session" || !cookies[0].HttpOnly || cookies[1].Name != "theme" {
+		t.Errorf("Unexpected cookies %v", w.Header().Values("Set-Cookie"))
+	}
+}
+
+func TestEncodeFailure(t *testing.T) {
+	w := httptest.NewRecorder()
+	resp := NewOK(w, WithData(func() {}), WithHeader("Location", "/users/1"))
+
+	err := resp.Send()
+	if !errors.Is(err, ErrEncoding) {
+		t.Fatalf("Expected ErrEncoding, got %v", err)
+	}
+	if resp.State() != StateFailed || resp.Err() != err {
+		t.Errorf("Expected the failure to be recorded, got %s with %v", resp.State(), resp.Err())
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if w.Header().Get("Location") != "" {
+		t.Errorf("Expected the headers of the failed response to be dropped, got %v", w.Header())
+	}
+
+	val, err := ExtractResponseBody[NewResponse](w.Result())
+	if err != nil {
+		t.Fatalf("Expected a valid envelope, got %v (%s)", err, w.Body.String())
+	}
+	if val.Success() || val.Message() != InternalServerErrorMessage {
+		t.Errorf("Unexpected envelope %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "func") {
+		t.Errorf("Expected the cause not to be sent, got %s", w.Body.String())
+	}
+}
+
+func TestHeadersWritten(t *testing.T) {
+	if HeadersWritten(httptest.NewRecorder()) {
+		t.Error("Expected an untracked writer to report no write")
+	}
+
+	w := &trackingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	wrapped := &wrappedWriter{ResponseWriter: w}
+	if HeadersWritten(wrapped) {
+		t.Error("Expected no write before WriteHeader")
+	}
+	w.WriteHeader(http.StatusCreated)
+	if !HeadersWritten(wrapped) {
+		t.Error("Expected the write to be found through Unwrap")
+	}
+}
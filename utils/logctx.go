@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns the log fields found in a context, such as a trace or tenant ID
+// Extractors run at log time for every entry logged with a context, and must be fast
+// Example: func(ctx context.Context) []utils.Option { return []utils.Option{utils.Field("tenant", tenantFrom(ctx))} }
+type ContextExtractor func(ctx context.Context) []Option
+
+// extractors holds the registered context extractors
+var extractors struct {
+	mu   sync.RWMutex
+	list []ContextExtractor
+}
+
+// RegisterContextExtractor adds an extractor used by every logger logging with a context
+// The request and correlation IDs are always extracted and need no extractor
+func RegisterContextExtractor(extractor ContextExtractor) {
+	if extractor == nil {
+		return
+	}
+	extractors.mu.Lock()
+	defer extractors.mu.Unlock()
+	extractors.list = append(extractors.list, extractor)
+}
+
+// ResetContextExtractors removes every registered extractor
+func ResetContextExtractors() {
+	extractors.mu.Lock()
+	defer extractors.mu.Unlock()
+	extractors.list = nil
+}
+
+// Ctx adds the fields found in ctx to the entry, the request IDs followed by those of the extractors
+// A context.Context passed directly to a log method is handled the same way
+// Example: logger.Info("Order created", utils.Ctx(r.Context()), utils.Field("order_id", id))
+func Ctx(ctx context.Context) Option {
+	return func(o *entryOptions) {
+		if ctx == nil {
+			return
+		}
+		if id := RequestIDFrom(ctx); id != "" {
+			o.fields = append(o.fields, FieldPair{Key: RequestIDField, Value: id})
+		}
+		if id := CorrelationIDFrom(ctx); id != "" {
+			o.fields = append(o.fields, FieldPair{Key: CorrelationIDField, Value: id})
+		}
+
+		extractors.mu.RLock()
+		list := extractors.list
+		extractors.mu.RUnlock()
+		for _, extractor := range list {
+			for _, opt := range extractor(ctx) {
+				opt(o)
+			}
+		}
+	}
+}
+
+// loggerKey is the context key of the request logger
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx holding the logger
+// Example: ctx = gecho.WithLogger(ctx, logger.WithField("job", job.ID))
+func WithLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFrom returns the logger stored in ctx, or the default logger, bound to ctx
+// Its entries include the fields extracted from ctx, see WithContext
+func LoggerFrom(ctx context.Context) *Logger {
+	logger := DefaultLogger()
+	if ctx != nil {
+		if stored, ok := ctx.Value(loggerKey{}).(*Logger); ok && stored != nil {
+			logger = stored
+		}
+	}
+	return logger.WithContext(ctx)
+}
+
+// WithContext returns a new logger whose entries include the fields extracted from ctx
+// The ...Ctx methods extract from their own context instead
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.clone()
+	child.ctx = ctx
+	return child
+}
+
+// DebugCtx logs a debug level message with the fields extracted from ctx
+func (l *Logger) DebugCtx(ctx context.Context, args ...any) {
+	msg, opts := parseArgs(args...)
+	l.log(LevelDebug, ctx, msg, opts)
+}
+
+// InfoCtx logs an info level message with the fields extracted from ctx
+func (l *Logger) InfoCtx(ctx context.Context, args ...any) {
+	msg, opts := parseArgs(args...)
+	l.log(LevelInfo, ctx, msg, opts)
+}
+
+// WarnCtx logs a warning level message with the fields extracted from ctx
+func (l *Logger) WarnCtx(ctx context.Context, args ...any) {
+	msg, opts := parseArgs(args...)
+	l.log(LevelWarn, ctx, msg, opts)
+}
+
+// ErrorCtx logs an error level message with the fields extracted from ctx
+func (l *Logger) ErrorCtx(ctx context.Context, args ...any) {
+	msg, opts := parseArgs(args...)
+	l.log(LevelError, ctx, msg, opts)
+}
+
+// FatalCtx logs a fatal level message with the fields extracted from ctx and exits the program
+func (l *Logger) FatalCtx(ctx context.Context, args ...any) {
+	msg, opts := parseArgs(args...)
+	l.log(LevelFatal, ctx, msg, opts)
+}
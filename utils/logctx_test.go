@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// tenantKey is the context key used by the test extractor
+type tenantKey struct{}
+
+// jsonLogger returns a JSON logger writing to buf, with the caller shown
+func jsonLogger(buf *bytes.Buffer) *Logger {
+	return NewLogger(NewConfig(WithOutput(buf), WithErrorOutput(buf), WithLogFormat(FormatJSON)))
+}
+
+func TestLoggerFrom(t *testing.T) {
+	if LoggerFrom(context.Background()) == nil || LoggerFrom(nil) == nil {
+		t.Fatal("Expected the default logger without a stored one")
+	}
+
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), jsonLogger(&buf).WithField("component", "billing"))
+	ctx = ContextWithRequestID(ctx, "req-1")
+
+	LoggerFrom(ctx).Info("Invoice sent")
+	if !strings.Contains(buf.String(), `"component":"billing"`) || !strings.Contains(buf.String(), `"request_id":"req-1"`) {
+		t.Errorf("Expected the stored logger bound to the context, got %s", buf.String())
+	}
+}
+
+func TestContextExtractors(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []Option {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []Option{Field("tenant", tenant)}
+		}
+		return nil
+	})
+	defer ResetContextExtractors()
+
+	var buf bytes.Buffer
+	logger := jsonLogger(&buf)
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	logger.InfoCtx(ctx, "Direct", Field("step", 1))
+	logger.WithContext(ctx).Warn("Bound")
+	logger.WithContext(ctx).WithField("job", "sync").Error("Bound with fields")
+	logger.Info("Without context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %q", buf.String())
+	}
+	for _, line := range lines[:3] {
+		if !strings.Contains(line, `"tenant":"acme"`) {
+			t.Errorf("Expected the extracted tenant, got %s", line)
+		}
+		if !strings.Contains(line, "logctx_test.go") {
+			t.Errorf("Expected the caller in the test file, got %s", line)
+		}
+	}
+	if !strings.Contains(lines[2], `"job":"sync"`) {
+		t.Errorf("Expected the persistent field to be kept, got %s", lines[2])
+	}
+	if strings.Contains(lines[3], "tenant") {
+		t.Errorf("Expected no extracted fields without a context, got %s", lines[3])
+	}
+}
+
+func TestCtxMethodReplacesBoundContext(t *testing.T) {
+	var buf bytes.Buffer
+	bound := jsonLogger(&buf).WithContext(ContextWithRequestID(context.Background(), "bound"))
+
+	bound.InfoCtx(ContextWithRequestID(context.Background(), "explicit"), "Replaced")
+	if strings.Contains(buf.String(), "bound") || strings.Count(buf.String(), "request_id") != 1 {
+		t.Errorf("Expected only the explicit context, got %s", buf.String())
+	}
+}
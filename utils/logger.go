@@ -1,14 +1,16 @@
 package utils
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
 	"os"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -78,6 +80,12 @@ type Config struct {
 	ShowCaller  bool
 	CallerSkip  int
 	TimeFormat  string
+	QueueSize   int            // Entries buffered by an asynchronous logger, zero writes synchronously
+	Overflow    OverflowPolicy // What an asynchronous logger does when its queue is full
+	Formatter   Formatter      // Custom format replacing Format and Colorize when set, see WithFormatter
+	Sinks       []Sink         // Destinations replacing Output and ErrorOutput when set, see WithSinks
+	LevelVar    *LevelVar      // Shared level replacing Level when set, see WithLevelVar
+	LevelRules  string         // Level rules such as "db.*=debug,http=warn", applied unless LevelVar is set
 }
 
 // DefaultConfig returns a logger config with sensible defaults
@@ -116,6 +124,13 @@ func WithLogFormat(format Format) LoggerOptions {
 	}
 }
 
+// WithFormatter writes entries with a custom Formatter instead of the built-in Format
+func WithFormatter(formatter Formatter) LoggerOptions {
+	return func(c *Config) {
+		c.Formatter = formatter
+	}
+}
+
 func WithColorize(colorize bool) LoggerOptions {
 	return func(c *Config) {
 		c.Colorize = colorize
@@ -134,6 +149,31 @@ func WithTimeFormat(timeFormat string) LoggerOptions {
 	}
 }
 
+// WithLevelRules sets the level per logger name with rules such as "info,db.*=debug,http=warn", see LevelVar.SetRules
+// A bare level in the rules wins over Level, however Level was set
+func WithLevelRules(rules string) LoggerOptions {
+	return func(c *Config) {
+		c.LevelRules = rules
+	}
+}
+
+// WithEnvLevels applies the level rules of the GECHO_LOG_LEVELS environment variable, see WithLevelRules
+// The rules replace those set before it, an unset or empty variable changes nothing
+func WithEnvLevels() LoggerOptions {
+	return func(c *Config) {
+		if rules := os.Getenv(LevelRulesEnv); rules != "" {
+			c.LevelRules = rules
+		}
+	}
+}
+
+// WithLevelVar makes the logger use a level shared with other loggers and changed at runtime
+func WithLevelVar(levelVar *LevelVar) LoggerOptions {
+	return func(c *Config) {
+		c.LevelVar = levelVar
+	}
+}
+
 func WithOutput(output io.Writer) LoggerOptions {
 	return func(c *Config) {
 		c.Output = output
@@ -157,14 +197,38 @@ type Logger struct {
 	mu     sync.Mutex
 	config Config
 	fields map[string]any
+	ctx    context.Context // Context whose fields are extracted at log time, see WithContext
+	slog   slog.Handler    // Handler receiving the entries instead of the writers, see NewLoggerFromSlog
+	async  *asyncQueue     // Queue of an asynchronous logger, shared with derived loggers, see WithAsync
+	sinks  []*sinkWriter   // Sinks of the logger, shared with derived loggers, see WithSinks
+	level  *LevelVar       // Level shared with derived loggers
+	name   string          // Name used for level overrides, see Named
 }
 
 // New creates a new logger with the given configuration
+// A positive QueueSize starts the writer goroutine of an asynchronous logger
+// The level is taken from LevelVar when set, otherwise from the bare level of LevelRules, otherwise from Level
 func NewLogger(config Config) *Logger {
-	return &Logger{
+	logger := &Logger{
 		config: config,
 		fields: make(map[string]any),
+		level:  config.LevelVar,
+	}
+	var rulesErr error
+	if logger.level == nil {
+		logger.level = NewLevelVar(config.Level)
+		rulesErr = logger.level.SetRules(config.LevelRules)
 	}
+	if config.QueueSize > 0 {
+		logger.async = newAsyncQueue(config.QueueSize, config.Overflow, logger.reportDropped)
+	}
+	if len(config.Sinks) > 0 {
+		logger.sinks = newSinkWriters(config.Sinks, config.TimeFormat)
+	}
+	if rulesErr != nil {
+		logger.Warn("Invalid log level rules", Field("rules", config.LevelRules), Field("error", rulesErr.Error()))
+	}
+	return logger
 }
 
 // NewDefaultLogger creates a new logger with default configuration
@@ -172,6 +236,27 @@ func NewDefaultLogger() *Logger {
 	return NewLogger(DefaultConfig())
 }
 
+// defaultLogger holds the logger set with SetDefaultLogger
+var defaultLogger atomic.Pointer[Logger]
+
+// fallbackLogger is used until a default logger is set
+var fallbackLogger = NewDefaultLogger()
+
+// SetDefaultLogger sets the logger used when no logger is provided,
+// such as for attached errors, auto-sent responses and event streams
+// A nil logger restores the built-in default
+func SetDefaultLogger(logger *Logger) {
+	defaultLogger.Store(logger)
+}
+
+// DefaultLogger returns the logger used when no logger is provided
+func DefaultLogger() *Logger {
+	if logger := defaultLogger.Load(); logger != nil {
+		return logger
+	}
+	return fallbackLogger
+}
+
 // WithField returns a new logger with an additional field
 func (l *Logger) WithField(key string, value any) *Logger {
 	return l.WithFields(map[string]any{key: value})
@@ -185,46 +270,93 @@ func (l *Logger) WithFields(fields map[string]any) *Logger {
 	newFields := maps.Clone(l.fields)
 	maps.Copy(newFields, fields)
 
+	child := l.clone()
+	child.fields = newFields
+	return child
+}
+
+// clone returns a copy of the logger sharing its configuration, the caller must hold l.mu
+func (l *Logger) clone() *Logger {
 	return &Logger{
 		config: l.config,
-		fields: newFields,
+		fields: l.fields,
+		ctx:    l.ctx,
+		slog:   l.slog,
+		async:  l.async,
+		sinks:  l.sinks,
+		level:  l.level,
+		name:   l.name,
 	}
 }
 
-// SetLevel sets the minimum log level
-func (l *Logger) SetLevel(level Level) {
+// Named returns a new logger whose entries carry its name in the logger field, and whose level can be overridden by name
+// Names of nested loggers are joined with dots, such as "db.pool", see LevelVar.SetOverride for the patterns
+func (l *Logger) Named(name string) *Logger {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.config.Level = level
+
+	child := l.clone()
+	if child.name != "" && name != "" {
+		child.name += "." + name
+	} else if name != "" {
+		child.name = name
+	}
+	return child
+}
+
+// Name returns the name of the logger, empty unless it was created with Named
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetLevel sets the minimum log level, for this logger and every logger sharing its level
+func (l *Logger) SetLevel(level Level) {
+	l.level.Set(level)
+}
+
+// Level returns the minimum log level of the logger, taking its name override into account
+func (l *Logger) Level() Level {
+	return l.level.LevelFor(l.name)
+}
+
+// LevelVar returns the level shared by the logger and the loggers derived from it
+func (l *Logger) LevelVar() *LevelVar {
+	return l.level
 }
 
-// SetFormat sets the output format
+// SetFormat sets the output format, replacing a custom Formatter
 func (l *Logger) SetFormat(format Format) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.config.Format = format
+	l.config.Formatter = nil
 }
 
-// fieldPair represents a key-value pair to preserve order
-type fieldPair struct {
+// FieldPair is a field of an entry, fields are kept in the order they were added
+type FieldPair struct {
 	Key   string
 	Value any
 }
 
-// entry represents a single log entry
-type entry struct {
-	Timestamp string      `json:"timestamp"`
-	Level     string      `json:"level"`
-	Message   string      `json:"message"`
-	Caller    string      `json:"caller,omitempty"`
-	Fields    []fieldPair `json:"fields,omitempty"`
+// Entry is a log entry, as handed to a Formatter
+type Entry struct {
+	Time      time.Time
+	Timestamp string // Time formatted with the TimeFormat of the logger
+	Level     Level
+	Logger    string // Name of the logger, see Named
+	Message   string
+	Caller    string // File and line of the log call, empty unless ShowCaller is set
+	Fields    []FieldPair
 }
 
 type Option func(*entryOptions)
 
 type entryOptions struct {
 	callerSkip *int
-	fields     []fieldPair
+	fields     []FieldPair
+	fromRecord bool      // Set for records coming from slog, which carry their own caller and time
+	pc         uintptr   // Program counter of the caller of a record, zero when unknown
+	time       time.Time // Time of a record
 }
 
 func WithCallerSkip(skip int) Option {
@@ -233,32 +365,44 @@ func WithCallerSkip(skip int) Option {
 	}
 }
 
-func (l *Logger) log(level Level, msg string, opts []Option) {
-	if level < l.config.Level {
+// log writes an entry, with the fields extracted from ctx when it is set
+func (l *Logger) log(level Level, ctx context.Context, msg string, opts []Option) {
+	if level < l.Level() {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
 	o := entryOptions{
-		fields: make([]fieldPair, 0),
+		fields: make([]FieldPair, 0),
 	}
 
+	// Context fields come first, extractors run without holding the lock
+	if ctx != nil {
+		Ctx(ctx)(&o)
+	}
 	for _, opt := range opts {
 		opt(&o)
 	}
 
-	e := entry{
-		Timestamp: time.Now().Format(l.config.TimeFormat),
-		Level:     level.String(),
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := o.time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	e := Entry{
+		Time:      now,
+		Timestamp: now.Format(l.config.TimeFormat),
+		Level:     level,
+		Logger:    l.name,
 		Message:   msg,
-		Fields:    make([]fieldPair, 0),
+		Fields:    make([]FieldPair, 0),
 	}
 
 	// Persistent fields
 	for k, v := range l.fields {
-		e.Fields = append(e.Fields, fieldPair{Key: k, Value: v})
+		e.Fields = append(e.Fields, FieldPair{Key: k, Value: v})
 	}
 
 	// Option fields (preserve order)
@@ -270,221 +414,93 @@ func (l *Logger) log(level Level, msg string, opts []Option) {
 		callerSkip = *o.callerSkip
 	}
 
-	if l.config.ShowCaller {
-		if _, file, line, ok := runtime.Caller(callerSkip); ok {
-			parts := strings.Split(file, "/")
-			e.Caller = fmt.Sprintf("%s:%d", parts[len(parts)-1], line)
+	pc := o.pc
+	if !o.fromRecord && (l.config.ShowCaller || l.slog != nil) {
+		var pcs [1]uintptr
+		if runtime.Callers(callerSkip+1, pcs[:]) > 0 {
+			pc = pcs[0]
 		}
 	}
 
-	// Output selection
-	output := l.config.Output
-	if level >= LevelError && l.config.ErrorOutput != nil {
-		output = l.config.ErrorOutput
-	}
-
-	// Write
-	if l.config.Format == FormatJSON {
-		l.writeJSON(output, e)
-	} else if l.config.Format == FormatPretty {
-		l.writePretty(output, level, e)
-	} else {
-		l.writeText(output, level, e)
-	}
-
-	if level == LevelFatal {
-		os.Exit(1)
-	}
-}
-
-var levelColors = map[Level]string{
-	LevelDebug: "\033[36m", // Cyan
-	LevelInfo:  "\033[32m", // Green
-	LevelWarn:  "\033[33m", // Yellow
-	LevelError: "\033[31m", // Red
-	LevelFatal: "\033[35m", // Magenta
-}
-
-const colorReset = "\033[0m"
-
-// writeText writes the entry in human-readable text format
-func (l *Logger) writeText(w io.Writer, level Level, e entry) {
-	var sb strings.Builder
-
-	// Timestamp
-	sb.WriteString(e.Timestamp)
-	sb.WriteString(" ")
-
-	// Level with optional color
-	if l.config.Colorize {
-		sb.WriteString(levelColors[level])
-	}
-	sb.WriteString(fmt.Sprintf("%-5s", e.Level))
-	if l.config.Colorize {
-		sb.WriteString(colorReset)
-	}
-	sb.WriteString(" ")
-
-	// Caller
-	if e.Caller != "" {
-		sb.WriteString("[")
-		sb.WriteString(e.Caller)
-		sb.WriteString("] ")
-	}
-
-	// Message
-	sb.WriteString(e.Message)
-
-	// Fields
-	if len(e.Fields) > 0 {
-		sb.WriteString(" {")
-		for i, f := range e.Fields {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(f.Key)
-			sb.WriteString("=")
-			sb.WriteString(fmt.Sprint(f.Value))
+	// Loggers created with NewLoggerFromSlog hand the entry to their slog handler
+	if l.slog != nil {
+		l.handleSlog(ctx, level, now, msg, pc, e.Fields)
+		if level == LevelFatal {
+			os.Exit(1)
 		}
-		sb.WriteString("}")
-	}
-
-	fmt.Fprintln(w, sb.String())
-}
-
-// writeJSON writes the entry in JSON format
-func (l *Logger) writeJSON(w io.Writer, e entry) {
-	// Convert fields to map for JSON output
-	fieldsMap := make(map[string]any)
-	for _, f := range e.Fields {
-		fieldsMap[f.Key] = f.Value
+		return
 	}
 
-	jsonEntry := struct {
-		Timestamp string         `json:"timestamp"`
-		Level     string         `json:"level"`
-		Message   string         `json:"message"`
-		Caller    string         `json:"caller,omitempty"`
-		Fields    map[string]any `json:"fields,omitempty"`
-	}{
-		Timestamp: e.Timestamp,
-		Level:     e.Level,
-		Message:   e.Message,
-		Caller:    e.Caller,
-		Fields:    fieldsMap,
+	if l.config.ShowCaller && pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		parts := strings.Split(frame.File, "/")
+		e.Caller = fmt.Sprintf("%s:%d", parts[len(parts)-1], frame.Line)
 	}
 
-	data, _ := json.Marshal(jsonEntry)
-	fmt.Fprintln(w, string(data))
-}
-
-// writePretty writes the entry in pretty format with parentheses around key-value pairs
-func (l *Logger) writePretty(w io.Writer, level Level, e entry) {
-	var sb strings.Builder
-
-	levelColor := levelColors[level]
-	colorize := l.config.Colorize
-
-	// Helper function to write with optional color
-	writeColored := func(color, text string) {
-		if colorize {
-			sb.WriteString(color)
-		}
-		sb.WriteString(text)
-		if colorize {
-			sb.WriteString(colorReset)
+	if len(l.sinks) > 0 {
+		l.writeSinks(&e)
+	} else {
+		// Output selection
+		output := l.config.Output
+		if level >= LevelError && l.config.ErrorOutput != nil {
+			output = l.config.ErrorOutput
 		}
+		l.emit(output, formatEntry(l.formatter(), &e))
 	}
 
-	// Timestamp (shortened format)
-	timestamp := e.Timestamp
-	if len(timestamp) > 18 {
-		// Extract just the time portion from "2006-01-02 15:04:05.000"
-		if parts := strings.Split(timestamp, " "); len(parts) >= 2 {
-			timestamp = parts[1][:12] // "15:04:05.000"
+	if level == LevelFatal {
+		if len(l.queues()) > 0 {
+			// The writers may need the lock to report dropped entries, os.Exit skips the deferred unlock
+			l.mu.Unlock()
+			l.Flush(context.Background())
 		}
+		os.Exit(1)
 	}
-	writeColored("\033[90m", timestamp) // Gray
-	sb.WriteString("  ")
-
-	// Level with optional color
-	writeColored(levelColor, fmt.Sprintf("%-5s", e.Level))
-	sb.WriteString("  ")
-
-	// Message
-	if e.Message != "" {
-		sb.WriteString(e.Message)
-	}
+}
 
-	// Fields in parentheses format (preserves order)
-	if len(e.Fields) > 0 {
-		if e.Message != "" {
-			sb.WriteString(" ")
-		}
-		for _, f := range e.Fields {
-			if colorize {
-				sb.WriteString(levelColor)
-			}
-			sb.WriteString("(")
-			if colorize {
-				sb.WriteString(colorReset)
-			}
-			sb.WriteString(f.Key)
-			if colorize {
-				sb.WriteString("\033[94m") // Light blue
-			}
-			sb.WriteString("=")
-			if colorize {
-				sb.WriteString(colorReset)
-			}
-			sb.WriteString(fmt.Sprint(f.Value))
-			if colorize {
-				sb.WriteString(levelColor)
-			}
-			sb.WriteString(") ")
-			if colorize {
-				sb.WriteString(colorReset)
-			}
-		}
+// emit writes a formatted entry, or queues it for the writer goroutine of an asynchronous logger
+func (l *Logger) emit(w io.Writer, data []byte) {
+	if l.async == nil || !l.async.enqueue(asyncRecord{w: w, data: data}) {
+		w.Write(data)
 	}
+}
 
-	// Caller at the end if present
-	if e.Caller != "" {
-		sb.WriteString(" ")
-		writeColored("\033[38;5;208m", fmt.Sprintf("[%s]", e.Caller)) // Orange
+// formatter returns the Formatter of the logger, or the built-in one of its Format
+func (l *Logger) formatter() Formatter {
+	if l.config.Formatter != nil {
+		return l.config.Formatter
 	}
-
-	fmt.Fprintln(w, strings.TrimRight(sb.String(), " "))
+	return FormatterFor(l.config.Format, l.config.Colorize)
 }
 
 // Debug logs a debug level message
 func (l *Logger) Debug(args ...any) {
 	msg, opts := parseArgs(args...)
-	l.log(LevelDebug, msg, opts)
+	l.log(LevelDebug, l.ctx, msg, opts)
 }
 
 // Info logs an info level message
 func (l *Logger) Info(args ...any) {
 	msg, opts := parseArgs(args...)
-	l.log(LevelInfo, msg, opts)
+	l.log(LevelInfo, l.ctx, msg, opts)
 }
 
 // Warn logs a warning level message
 func (l *Logger) Warn(args ...any) {
 	msg, opts := parseArgs(args...)
-	l.log(LevelWarn, msg, opts)
+	l.log(LevelWarn, l.ctx, msg, opts)
 }
 
 // Error logs an error level message
 func (l *Logger) Error(args ...any) {
 	msg, opts := parseArgs(args...)
-	l.log(LevelError, msg, opts)
+	l.log(LevelError, l.ctx, msg, opts)
 }
 
 // Fatal logs a fatal level message and exits the program
 func (l *Logger) Fatal(args ...any) {
 	msg, opts := parseArgs(args...)
-	l.log(LevelFatal, msg, opts)
+	l.log(LevelFatal, l.ctx, msg, opts)
 }
 
 // isTerminal checks if the writer is a terminal
@@ -502,9 +518,9 @@ func isTerminal(w io.Writer) bool {
 func Field(key string, value any) Option {
 	return func(o *entryOptions) {
 		if o.fields == nil {
-			o.fields = make([]fieldPair, 0)
+			o.fields = make([]FieldPair, 0)
 		}
-		o.fields = append(o.fields, fieldPair{Key: key, Value: value})
+		o.fields = append(o.fields, FieldPair{Key: key, Value: value})
 	}
 }
 
@@ -519,16 +535,15 @@ func parseArgs(args ...any) (string, []Option) {
 	// First argument may be a message string
 	if s, ok := args[0].(string); ok {
 		msg = s
-		for _, a := range args[1:] {
-			if opt, ok := a.(Option); ok {
-				opts = append(opts, opt)
-			}
-		}
-	} else {
-		for _, a := range args {
-			if opt, ok := a.(Option); ok {
-				opts = append(opts, opt)
-			}
+		args = args[1:]
+	}
+
+	for _, a := range args {
+		switch v := a.(type) {
+		case Option:
+			opts = append(opts, v)
+		case context.Context:
+			opts = append(opts, Ctx(v))
 		}
 	}
 
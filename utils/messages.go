@@ -0,0 +1,59 @@
+// Code generated by go run ./internal/genstatus; DO NOT EDIT.
+
+package utils
+
+// Default messages of the status helpers
+const SuccessMessage = "Success"
+const CreatedMessage = "Resource Created"
+const AcceptedMessage = "Accepted"
+const NonAuthoritativeInfoMessage = "Non-authoritative information"
+const NoContentMessage = "No Content"
+const ResetContentMessage = "Reset content"
+const PartialContentMessage = "Partial content"
+const MultiStatusMessage = "Multi-status"
+const AlreadyReportedMessage = "Already reported"
+const MovedPermanentlyMessage = "Moved permanently"
+const FoundMessage = "Found"
+const SeeOtherMessage = "See other"
+const TemporaryRedirectMessage = "Temporary redirect"
+const PermanentRedirectMessage = "Permanent redirect"
+const BadRequestMessage = "Bad request"
+const UnauthorizedMessage = "Unauthorized"
+const PaymentRequiredMessage = "Payment required"
+const ForbiddenMessage = "Forbidden"
+const NotFoundMessage = "Resource not found"
+const MethodNotAllowedMessage = "Method not allowed"
+const NotAcceptableMessage = "Not acceptable"
+const ProxyAuthRequiredMessage = "Proxy authentication required"
+const RequestTimeoutMessage = "Request timeout"
+const ConflictMessage = "Conflict"
+const GoneMessage = "Resource gone"
+const LengthRequiredMessage = "Length required"
+const PreconditionFailedMessage = "Precondition failed"
+const RequestEntityTooLargeMessage = "Request entity too large"
+const RequestURITooLongMessage = "Request URI too long"
+const UnsupportedMediaTypeMessage = "Unsupported media type"
+const RequestedRangeNotSatisfiableMessage = "Requested range not satisfiable"
+const ExpectationFailedMessage = "Expectation failed"
+const TeapotMessage = "I'm a teapot"
+const MisdirectedRequestMessage = "Misdirected request"
+const UnprocessableEntityMessage = "Unprocessable entity"
+const LockedMessage = "Resource locked"
+const FailedDependencyMessage = "Failed dependency"
+const TooEarlyMessage = "Too early"
+const UpgradeRequiredMessage = "Upgrade required"
+const PreconditionRequiredMessage = "Precondition required"
+const TooManyRequestsMessage = "Too many requests"
+const RequestHeaderFieldsTooLargeMessage = "Request header fields too large"
+const UnavailableForLegalReasonsMessage = "Unavailable for legal reasons"
+const InternalServerErrorMessage = "Internal server error"
+const NotImplementedMessage = "Not implemented"
+const BadGatewayMessage = "Bad gateway"
+const ServiceUnavailableMessage = "Service unavailable"
+const GatewayTimeoutMessage = "Gateway timeout"
+const HTTPVersionNotSupportedMessage = "HTTP version not supported"
+const VariantAlsoNegotiatesMessage = "Variant also negotiates"
+const InsufficientStorageMessage = "Insufficient storage"
+const LoopDetectedMessage = "Loop detected"
+const NotExtendedMessage = "Not extended"
+const NetworkAuthenticationRequiredMessage = "Network authentication required"
@@ -0,0 +1,336 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query parameters used by ParsePageParams and in Link headers
+const (
+	PageParam   = "page"
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+)
+
+// Pagination defaults used by ParsePageParams
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded or its signature does not match
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page describes the page of items returned by a list endpoint
+// Set Page for offset pagination, or NextCursor/PrevCursor for cursor pagination
+type Page struct {
+	Page       int    // 1-based page number, offset pagination only
+	PerPage    int    // Number of items per page
+	Total      int    // Total number of items, required for offset pagination
+	NextCursor string // Opaque cursor of the next page, empty on the last page
+	PrevCursor string // Opaque cursor of the previous page, empty on the first page
+}
+
+// isOffset reports whether the page uses offset pagination
+func (p Page) isOffset() bool {
+	return p.Page > 0
+}
+
+// lastPage returns the number of the last page, at least 1
+func (p Page) lastPage() int {
+	if p.PerPage <= 0 || p.Total <= 0 {
+		return 1
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// Map returns the pagination metadata sent in the meta block of the envelope
+func (p Page) Map() map[string]any {
+	meta := map[string]any{"per_page": p.PerPage}
+	if p.isOffset() {
+		meta["page"] = p.Page
+		meta["total"] = p.Total
+		meta["total_pages"] = p.lastPage()
+		return meta
+	}
+
+	if p.Total > 0 {
+		meta["total"] = p.Total
+	}
+	if p.NextCursor != "" {
+		meta["next_cursor"] = p.NextCursor
+	}
+	if p.PrevCursor != "" {
+		meta["prev_cursor"] = p.PrevCursor
+	}
+	return meta
+}
+
+// linkHeader builds an RFC 8288 Link header with first, prev, next and last relations
+func (p Page) linkHeader(r *http.Request) string {
+	base := requestURL(r)
+	links := make([]string, 0, 4)
+
+	link := func(rel string, params map[string]string) {
+		u := *base
+		query := u.Query()
+		query.Del(PageParam)
+		query.Del(CursorParam)
+		for key, value := range params {
+			query.Set(key, value)
+		}
+		if p.PerPage > 0 {
+			query.Set(LimitParam, strconv.Itoa(p.PerPage))
+		}
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+
+	if p.isOffset() {
+		last := p.lastPage()
+		link("first", map[string]string{PageParam: "1"})
+		if p.Page > 1 {
+			link("prev", map[string]string{PageParam: strconv.Itoa(min(p.Page-1, last))})
+		}
+		if p.Page < last {
+			link("next", map[string]string{PageParam: strconv.Itoa(p.Page + 1)})
+		}
+		link("last", map[string]string{PageParam: strconv.Itoa(last)})
+	} else {
+		link("first", nil)
+		if p.PrevCursor != "" {
+			link("prev", map[string]string{CursorParam: p.PrevCursor})
+		}
+		if p.NextCursor != "" {
+			link("next", map[string]string{CursorParam: p.NextCursor})
+		}
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// requestURL reconstructs the absolute URL of a request
+func requestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return &u
+}
+
+// WithPagination adds pagination metadata to the envelope and a Link header to the response
+// The Link header requires the request, see WithRequest
+func WithPagination(page Page) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.page = &page
+		if rc.meta == nil {
+			rc.meta = make(map[string]any)
+		}
+		rc.meta["pagination"] = page.Map()
+	}
+}
+
+// Paginated creates a success response for a page of items
+// Example: utils.Paginated(w, r, users, utils.Page{Page: 2, PerPage: 20, Total: 95}, utils.Send())
+func Paginated(w http.ResponseWriter, r *http.Request, items any, page Page, opts ...ResponseOption) *Response {
+	allOpts := []ResponseOption{
+		WithRequest(r),
+		WithData(items),
+		WithPagination(page),
+	}
+	allOpts = append(allOpts, opts...)
+	return NewOK(w, allOpts...)
+}
+
+// CursorCodec encodes values into opaque, optionally signed, cursor strings
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec creates a codec that signs cursors with HMAC-SHA256
+// A nil secret produces unsigned cursors that are only encoded
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// Encode encodes v as a URL-safe cursor
+func (c *CursorCodec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString(payload)
+	if len(c.secret) > 0 {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+	}
+	return cursor, nil
+}
+
+// Decode verifies the cursor and decodes it into v, returning ErrInvalidCursor on failure
+func (c *CursorCodec) Decode(cursor string, v any) error {
+	encoded, signature, signed := strings.Cut(cursor, ".")
+	if signed != (len(c.secret) > 0) {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if signed {
+		mac, err := base64.RawURLEncoding.DecodeString(signature)
+		if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+			return ErrInvalidCursor
+		}
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 of the payload
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// PageParams holds the pagination parameters of a request
+type PageParams struct {
+	Page   int    // 1-based page number, 1 when absent
+	Limit  int    // Number of items per page
+	Cursor string // Opaque cursor, empty when absent
+	codec  *CursorCodec
+}
+
+// Offset returns the number of items to skip for offset pagination
+func (pp PageParams) Offset() int {
+	return (pp.Page - 1) * pp.Limit
+}
+
+// DecodeCursor decodes the cursor into v using the codec passed to ParsePageParams
+func (pp PageParams) DecodeCursor(v any) error {
+	if pp.codec == nil {
+		return ErrInvalidCursor
+	}
+	return pp.codec.Decode(pp.Cursor, v)
+}
+
+// PageParamsOption is a function that configures ParsePageParams
+type PageParamsOption func(*pageParamsConfig)
+
+// pageParamsConfig holds the configuration of ParsePageParams
+type pageParamsConfig struct {
+	defaultLimit int
+	maxLimit     int
+	codec        *CursorCodec
+}
+
+// WithDefaultLimit sets the limit used when the request does not specify one
+func WithDefaultLimit(limit int) PageParamsOption {
+	return func(pc *pageParamsConfig) {
+		pc.defaultLimit = limit
+	}
+}
+
+// WithMaxLimit sets the maximum accepted limit
+func WithMaxLimit(limit int) PageParamsOption {
+	return func(pc *pageParamsConfig) {
+		pc.maxLimit = limit
+	}
+}
+
+// WithCursorCodec verifies cursors with the codec, rejecting tampered ones
+func WithCursorCodec(codec *CursorCodec) PageParamsOption {
+	return func(pc *pageParamsConfig) {
+		pc.codec = codec
+	}
+}
+
+// ParsePageParams parses the page, limit and cursor query parameters
+// On invalid input a 400 Bad Request is sent with the offending field and reason, and ok is false
+// Example: params, ok := utils.ParsePageParams(w, r, utils.WithMaxLimit(50)); if !ok { return }
+func ParsePageParams(w http.ResponseWriter, r *http.Request, opts ...PageParamsOption) (PageParams, bool) {
+	config := &pageParamsConfig{
+		defaultLimit: DefaultPageLimit,
+		maxLimit:     MaxPageLimit,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	params, field, reason := parsePageParams(r.URL.Query(), config)
+	if field != "" {
+		NewErrFor(w, r,
+			WithStatus(http.StatusBadRequest),
+			WithMessage(BadRequestMessage),
+			WithData(map[string]any{"field": field, "reason": reason}),
+			Send(),
+		)
+		return PageParams{}, false
+	}
+	return params, true
+}
+
+// parsePageParams validates the query, returning the offending field and reason on failure
+func parsePageParams(query url.Values, config *pageParamsConfig) (PageParams, string, string) {
+	params := PageParams{Page: 1, Limit: config.defaultLimit, codec: config.codec}
+
+	if raw := query.Get(LimitParam); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return params, LimitParam, "must be a positive integer"
+		}
+		if limit > config.maxLimit {
+			return params, LimitParam, fmt.Sprintf("must be at most %d", config.maxLimit)
+		}
+		params.Limit = limit
+	}
+
+	rawPage := query.Get(PageParam)
+	params.Cursor = query.Get(CursorParam)
+
+	if rawPage != "" && params.Cursor != "" {
+		return params, PageParam, "cannot be combined with " + CursorParam
+	}
+
+	if rawPage != "" {
+		page, err := strconv.Atoi(rawPage)
+		if err != nil || page < 1 {
+			return params, PageParam, "must be a positive integer"
+		}
+		// The offset of the page must fit in an int
+		if params.Limit > 0 && page-1 > math.MaxInt/params.Limit {
+			return params, PageParam, "is too large"
+		}
+		params.Page = page
+	}
+
+	if params.Cursor != "" && config.codec != nil {
+		var payload json.RawMessage
+		if err := config.codec.Decode(params.Cursor, &payload); err != nil {
+			return params, CursorParam, "is invalid or has been tampered with"
+		}
+	}
+
+	return params, "", ""
+}
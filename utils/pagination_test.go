@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPaginatedOffset(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "http://api.example.com/users?page=2&limit=10&sort=name", nil)
+
+	Paginated(w, r, []string{"a", "b"}, Page{Page: 2, PerPage: 10, Total: 35}, Send())
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Data []string `json:"data"`
+		Meta struct {
+			Pagination map[string]int `json:"pagination"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	pagination := body.Meta.Pagination
+	if pagination["page"] != 2 || pagination["per_page"] != 10 || pagination["total"] != 35 || pagination["total_pages"] != 4 {
+		t.Errorf("Unexpected pagination metadata %v", pagination)
+	}
+	if len(body.Data) != 2 {
+		t.Errorf("Expected 2 items, got %v", body.Data)
+	}
+
+	link := w.Header().Get("Link")
+	for _, expected := range []string{
+		`<http://api.example.com/users?limit=10&page=1&sort=name>; rel="first"`,
+		`<http://api.example.com/users?limit=10&page=1&sort=name>; rel="prev"`,
+		`<http://api.example.com/users?limit=10&page=3&sort=name>; rel="next"`,
+		`<http://api.example.com/users?limit=10&page=4&sort=name>; rel="last"`,
+	} {
+		if !strings.Contains(link, expected) {
+			t.Errorf("Expected Link header to contain %s, got %s", expected, link)
+		}
+	}
+}
+
+func TestPaginatedOffsetBoundaries(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	Paginated(w, r, []string{}, Page{Page: 1, PerPage: 10, Total: 5}, Send())
+
+	link := w.Header().Get("Link")
+	if strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="next"`) {
+		t.Errorf("Expected no prev or next links on a single page, got %s", link)
+	}
+	if !strings.Contains(link, `rel="first"`) || !strings.Contains(link, `rel="last"`) {
+		t.Errorf("Expected first and last links, got %s", link)
+	}
+}
+
+func TestPaginatedCursor(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events?cursor=abc", nil)
+
+	Paginated(w, r, []int{1, 2}, Page{PerPage: 2, NextCursor: "def"}, Send())
+
+	var body struct {
+		Meta struct {
+			Pagination map[string]any `json:"pagination"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	pagination := body.Meta.Pagination
+	if pagination["next_cursor"] != "def" {
+		t.Errorf("Expected next_cursor 'def', got %v", pagination["next_cursor"])
+	}
+	if _, ok := pagination["page"]; ok {
+		t.Errorf("Expected no page in cursor pagination, got %v", pagination)
+	}
+
+	link := w.Header().Get("Link")
+	if !strings.Contains(link, `<http://example.com/events?cursor=def&limit=2>; rel="next"`) {
+		t.Errorf("Expected next cursor link, got %s", link)
+	}
+	if strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="last"`) {
+		t.Errorf("Expected no prev or last links, got %s", link)
+	}
+}
+
+func TestWithMeta(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewOK(w, WithMeta("took_ms", 12), Send())
+
+	if !strings.Contains(w.Body.String(), `"meta":{"took_ms":12}`) {
+		t.Errorf("Expected meta block in body, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	NewOK(w, Send())
+	if strings.Contains(w.Body.String(), `"meta"`) {
+		t.Errorf("Expected no meta block without metadata, got %s", w.Body.String())
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	type position struct {
+		ID int `json:"id"`
+	}
+
+	codec := NewCursorCodec([]byte("secret"))
+	cursor, err := codec.Encode(position{ID: 42})
+	if err != nil {
+		t.Fatalf("Expected no error on Encode(), got %v", err)
+	}
+
+	var decoded position
+	if err := codec.Decode(cursor, &decoded); err != nil || decoded.ID != 42 {
+		t.Errorf("Expected id 42, got %v (error %v)", decoded.ID, err)
+	}
+
+	t.Run("Tampered", func(t *testing.T) {
+		forged, _ := NewCursorCodec([]byte("other")).Encode(position{ID: 1})
+		if err := codec.Decode(forged, &decoded); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		plain := NewCursorCodec(nil)
+		cursor, _ := plain.Encode(position{ID: 7})
+		if strings.Contains(cursor, ".") {
+			t.Errorf("Expected unsigned cursor, got %s", cursor)
+		}
+		if err := codec.Decode(cursor, &decoded); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected signed codec to reject unsigned cursor, got %v", err)
+		}
+	})
+}
+
+func TestParsePageParams(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	validCursor, _ := codec.Encode(map[string]int{"id": 5})
+
+	tests := []struct {
+		name   string
+		query  string
+		ok     bool
+		page   int
+		limit  int
+		offset int
+		field  string
+	}{
+		{"Defaults", "", true, 1, DefaultPageLimit, 0, ""},
+		{"PageAndLimit", "page=3&limit=10", true, 3, 10, 20, ""},
+		{"ValidCursor", "cursor=" + validCursor, true, 1, DefaultPageLimit, 0, ""},
+		{"InvalidPage", "page=zero", false, 0, 0, 0, "page"},
+		{"PageTooLarge", "limit=100&page=" + strconv.Itoa(math.MaxInt/100+2), false, 0, 0, 0, "page"},
+		{"LargestPage", "limit=100&page=" + strconv.Itoa(math.MaxInt/100+1), true, math.MaxInt/100 + 1, 100, math.MaxInt / 100 * 100, ""},
+		{"NegativeLimit", "limit=-1", false, 0, 0, 0, "limit"},
+		{"LimitTooLarge", "limit=500", false, 0, 0, 0, "limit"},
+		{"PageWithCursor", "page=2&cursor=" + validCursor, false, 0, 0, 0, "page"},
+		{"TamperedCursor", "cursor=" + validCursor + "x", false, 0, 0, 0, "cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil)
+
+			params, ok := ParsePageParams(w, r, WithCursorCodec(codec))
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("Expected status 400, got %d", w.Code)
+				}
+				if !strings.Contains(w.Body.String(), `"field":"`+tt.field+`"`) {
+					t.Errorf("Expected field %s in body, got %s", tt.field, w.Body.String())
+				}
+				return
+			}
+
+			if w.Body.Len() != 0 {
+				t.Errorf("Expected nothing written on success, got %s", w.Body.String())
+			}
+			if params.Page != tt.page || params.Limit != tt.limit || params.Offset() != tt.offset {
+				t.Errorf("Expected page %d, limit %d, offset %d, got %d, %d, %d",
+					tt.page, tt.limit, tt.offset, params.Page, params.Limit, params.Offset())
+			}
+		})
+	}
+
+	t.Run("DecodeCursor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/items?cursor="+validCursor, nil)
+
+		params, _ := ParsePageParams(w, r, WithCursorCodec(codec), WithMaxLimit(50))
+		var position map[string]int
+		if err := params.DecodeCursor(&position); err != nil || position["id"] != 5 {
+			t.Errorf("Expected id 5, got %v (error %v)", position, err)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"sync/atomic"
+)
+
+// ProblemContentType is the media type used for RFC 9457 problem details documents
+const ProblemContentType = "application/problem+json"
+
+// ProblemXMLContentType is the media type used for problem details documents encoded as XML
+const ProblemXMLContentType = "application/problem+xml"
+
+// problemXMLNamespace is the XML namespace defined for problem details documents
+const problemXMLNamespace = "urn:ietf:rfc:7807"
+
+// DefaultProblemType is the problem type used when no type URI is provided
+const DefaultProblemType = "about:blank"
+
+// ErrorFormat selects how error responses are rendered
+type ErrorFormat int
+
+const (
+	// ErrorFormatEnvelope renders errors using the standard gecho envelope
+	ErrorFormatEnvelope ErrorFormat = iota
+	// ErrorFormatProblem renders errors as RFC 9457 problem details documents
+	ErrorFormatProblem
+)
+
+// errorFormat holds the global error format, shared by all responses
+var errorFormat atomic.Int32
+
+// SetErrorFormat sets the global format used to render error responses
+// Success responses always use the standard envelope
+func SetErrorFormat(format ErrorFormat) {
+	errorFormat.Store(int32(format))
+}
+
+// GetErrorFormat returns the global format used to render error responses
+func GetErrorFormat() ErrorFormat {
+	return ErrorFormat(errorFormat.Load())
+}
+
+// ProblemDetails is an RFC 9457 problem details document
+type ProblemDetails struct {
+	Type       string         // URI reference identifying the problem type
+	Title      string         // Short, human-readable summary of the problem type
+	Status     int            // HTTP status code generated by the origin server
+	Detail     string         // Human-readable explanation specific to this occurrence
+	Instance   string         // URI reference identifying this specific occurrence
+	Extensions map[string]any // Additional members, serialized next to the standard ones
+}
+
+// problemMembers lists the standard members that extensions may not override
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// MarshalJSON flattens extension members into the top level of the document
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Map())
+}
+
+// MarshalXML encodes the document as described in Appendix B of RFC 9457
+func (p ProblemDetails) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{
+		Name: xml.Name{Space: problemXMLNamespace, Local: "problem"},
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	doc := p.Map()
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := encodeXMLValue(e, key, doc[key]); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// Map returns the document as a flat map of members
+func (p ProblemDetails) Map() map[string]any {
+	doc := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		if !problemMembers[key] {
+			doc[key] = value
+		}
+	}
+
+	problemType := p.Type
+	if problemType == "" {
+		problemType = DefaultProblemType
+	}
+	doc["type"] = problemType
+	doc["status"] = p.Status
+
+	if p.Title != "" {
+		doc["title"] = p.Title
+	}
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+
+	return doc
+}
+
+// problemMediaType returns the problem variant of the negotiated media type
+func problemMediaType(mediaType string) string {
+	switch mediaType {
+	case MediaTypeJSON:
+		return ProblemContentType
+	case MediaTypeXML:
+		return ProblemXMLContentType
+	}
+	return mediaType
+}
+
+// UnmarshalJSON reads the standard members and collects everything else as extensions
+func (p *ProblemDetails) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*p = ProblemDetails{}
+	for key, value := range raw {
+		var err error
+		switch key {
+		case "type":
+			err = json.Unmarshal(value, &p.Type)
+		case "title":
+			err = json.Unmarshal(value, &p.Title)
+		case "status":
+			err = json.Unmarshal(value, &p.Status)
+		case "detail":
+			err = json.Unmarshal(value, &p.Detail)
+		case "instance":
+			err = json.Unmarshal(value, &p.Instance)
+		default:
+			var ext any
+			err = json.Unmarshal(value, &ext)
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]any)
+			}
+			p.Extensions[key] = ext
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.Type == "" {
+		p.Type = DefaultProblemType
+	}
+
+	return nil
+}
+
+// problemOptions holds the problem details configuration of a response
+type problemOptions struct {
+	format     *ErrorFormat
+	typeURI    string
+	title      string
+	instance   string
+	extensions map[string]any
+}
+
+// enabled reports whether the response should be rendered as a problem document
+func (po problemOptions) enabled() bool {
+	if po.format != nil {
+		return *po.format == ErrorFormatProblem
+	}
+	return GetErrorFormat() == ErrorFormatProblem
+}
+
+// WithErrorFormat overrides the global error format for a single response
+func WithErrorFormat(format ErrorFormat) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.problem.format = &format
+	}
+}
+
+// WithProblemType sets the problem type URI of an error response
+func WithProblemType(uri string) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.problem.typeURI = uri
+	}
+}
+
+// WithProblemTitle sets the problem title, defaults to the HTTP status text
+func WithProblemTitle(title string) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.problem.title = title
+	}
+}
+
+// WithInstance sets the URI reference identifying this occurrence of the problem
+func WithInstance(path string) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.problem.instance = path
+	}
+}
+
+// WithExtension adds an extension member to the problem details document
+func WithExtension(key string, value any) ResponseOption {
+	return func(rc *responseConfig) {
+		if rc.problem.extensions == nil {
+			rc.problem.extensions = make(map[string]any)
+		}
+		rc.problem.extensions[key] = value
+	}
+}
+
+// newProblemDetails builds a problem document from the response values
+// Map data is merged into the extension members, any other data is kept under "data"
+func newProblemDetails(status int, message string, data any, po problemOptions) ProblemDetails {
+	title := po.title
+	if title == "" {
+		title = http.StatusText(status)
+	}
+
+	extensions := make(map[string]any)
+	switch v := data.(type) {
+	case nil:
+	case map[string]any:
+		for key, value := range v {
+			extensions[key] = value
+		}
+	case map[string]string:
+		for key, value := range v {
+			extensions[key] = value
+		}
+	default:
+		extensions["data"] = v
+	}
+
+	for key, value := range po.extensions {
+		extensions[key] = value
+	}
+
+	return ProblemDetails{
+		Type:       po.typeURI,
+		Title:      title,
+		Status:     status,
+		Detail:     message,
+		Instance:   po.instance,
+		Extensions: extensions,
+	}
+}
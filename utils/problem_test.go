@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewErr(w,
+		WithStatus(http.StatusNotFound),
+		WithMessage("User 42 does not exist"),
+		WithErrorFormat(ErrorFormatProblem),
+		WithProblemType("https://example.com/problems/not-found"),
+		WithInstance("/users/42"),
+		WithExtension("user_id", 42),
+		Send(),
+	)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", ProblemContentType, ct)
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+
+	if problem.Type != "https://example.com/problems/not-found" {
+		t.Errorf("Expected type to be set, got '%s'", problem.Type)
+	}
+
+	if problem.Title != http.StatusText(http.StatusNotFound) {
+		t.Errorf("Expected title '%s', got '%s'", http.StatusText(http.StatusNotFound), problem.Title)
+	}
+
+	if problem.Status != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, problem.Status)
+	}
+
+	if problem.Detail != "User 42 does not exist" {
+		t.Errorf("Expected detail 'User 42 does not exist', got '%s'", problem.Detail)
+	}
+
+	if problem.Instance != "/users/42" {
+		t.Errorf("Expected instance '/users/42', got '%s'", problem.Instance)
+	}
+
+	if problem.Extensions["user_id"] != float64(42) {
+		t.Errorf("Expected extension user_id=42, got %v", problem.Extensions["user_id"])
+	}
+}
+
+func TestProblemResponseDataMerge(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewErr(w,
+		WithStatus(http.StatusBadRequest),
+		WithErrorFormat(ErrorFormatProblem),
+		WithData(map[string]any{"field": "email", "status": "ignored"}),
+		Send(),
+	)
+
+	var doc map[string]any
+	if err := json.NewDecoder(w.Result().Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+
+	if doc["field"] != "email" {
+		t.Errorf("Expected map data to be merged as extension, got %v", doc)
+	}
+
+	if doc["status"] != float64(http.StatusBadRequest) {
+		t.Errorf("Expected extension not to override status, got %v", doc["status"])
+	}
+
+	if doc["type"] != DefaultProblemType {
+		t.Errorf("Expected default type '%s', got %v", DefaultProblemType, doc["type"])
+	}
+}
+
+func TestGlobalErrorFormat(t *testing.T) {
+	SetErrorFormat(ErrorFormatProblem)
+	defer SetErrorFormat(ErrorFormatEnvelope)
+
+	t.Run("ErrorUsesProblem", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewErr(w, WithStatus(http.StatusConflict), Send())
+
+		if ct := w.Result().Header.Get("Content-Type"); ct != ProblemContentType {
+			t.Errorf("Expected Content-Type '%s', got '%s'", ProblemContentType, ct)
+		}
+	})
+
+	t.Run("SuccessKeepsEnvelope", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewOK(w, Send())
+
+		var response NewResponse
+		if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if !response.Success() || response.Message() != "Success" {
+			t.Errorf("Expected standard success envelope, got %+v", response)
+		}
+	})
+
+	t.Run("InstanceOverride", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewErr(w, WithErrorFormat(ErrorFormatEnvelope), Send())
+
+		if ct := w.Result().Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected Content-Type 'application/json', got '%s'", ct)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// Headers carrying the IDs of a request
+const (
+	RequestIDHeader     = "X-Request-ID"
+	CorrelationIDHeader = "X-Correlation-ID"
+)
+
+// Log fields holding the IDs of a request
+const (
+	RequestIDField     = "request_id"
+	CorrelationIDField = "correlation_id"
+)
+
+// maxRequestIDLength limits the length of IDs accepted from clients
+const maxRequestIDLength = 128
+
+// IDGenerator returns a new unique ID
+type IDGenerator func() string
+
+// requestIDKey and correlationIDKey are the context keys of the request IDs
+type requestIDKey struct{}
+type correlationIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx holding the request ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID stored in ctx, or an empty string
+func RequestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithCorrelationID returns a copy of ctx holding the correlation ID
+// The correlation ID is shared by every request made on behalf of the same original request
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFrom returns the correlation ID stored in ctx, or an empty string
+func CorrelationIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// ValidRequestID reports whether an ID received from a client is safe to reuse
+// IDs must be at most 128 characters of letters, digits and -_.:/+=
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewUUIDv7 returns a time-ordered UUID as described in RFC 9562
+func NewUUIDv7() string {
+	var u [16]byte
+	rand.Read(u[6:])
+	ms := uint64(time.Now().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	u[6] = u[6]&0x0f | 0x70 // Version 7
+	u[8] = u[8]&0x3f | 0x80 // Variant 10
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// crockford is the base32 alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a lexicographically sortable ID, a millisecond timestamp followed by 80 random bits
+func NewULID() string {
+	var u [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	rand.Read(u[6:])
+
+	// 26 characters of 5 bits encode the 128 bits, the first one only uses 3 of them
+	var buf [26]byte
+	for i := range buf {
+		bit := i*5 - 2
+		var v byte
+		for j := range 5 {
+			b := bit + j
+			v <<= 1
+			if b >= 0 && u[b/8]&(0x80>>(b%8)) != 0 {
+				v |= 1
+			}
+		}
+		buf[i] = crockford[v]
+	}
+	return string(buf[:])
+}
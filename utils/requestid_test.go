@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUUIDv7(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first := NewUUIDv7()
+	if !pattern.MatchString(first) {
+		t.Fatalf("Expected a version 7 UUID, got %s", first)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	second := NewUUIDv7()
+	if second <= first {
+		t.Errorf("Expected UUIDs to be time ordered, got %s after %s", second, first)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+	first := NewULID()
+	if !pattern.MatchString(first) {
+		t.Fatalf("Expected a ULID, got %s", first)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	second := NewULID()
+	if second <= first {
+		t.Errorf("Expected ULIDs to be sortable, got %s after %s", second, first)
+	}
+	if first == NewULID() {
+		t.Error("Expected unique ULIDs")
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{"0190f1a2-7b3c-7def-8abc-1234567890ab", true},
+		{"01J3Z5Q8X9ABCDEFGHJKMNPQRS", true},
+		{"trace:abc/def+1=", true},
+		{"", false},
+		{"has space", false},
+		{"new\nline", false},
+		{"<script>", false},
+		{strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidRequestID(tt.id); got != tt.expected {
+			t.Errorf("ValidRequestID(%q) = %v, expected %v", tt.id, got, tt.expected)
+		}
+	}
+}
+
+func TestRequestIDContext(t *testing.T) {
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+
+	if RequestIDFrom(ctx) != "req-1" || CorrelationIDFrom(ctx) != "corr-1" {
+		t.Errorf("Expected the stored IDs, got %q and %q", RequestIDFrom(ctx), CorrelationIDFrom(ctx))
+	}
+	if RequestIDFrom(context.Background()) != "" || RequestIDFrom(nil) != "" {
+		t.Error("Expected no ID without one stored")
+	}
+}
+
+func TestLoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewConfig(WithOutput(&buf), WithLogFormat(FormatJSON)))
+	ctx := ContextWithCorrelationID(ContextWithRequestID(context.Background(), "req-1"), "corr-1")
+
+	logger.Info("Direct context", ctx)
+	logger.Info("Ctx option", Ctx(ctx), Field("order_id", 7))
+	logger.Info("No IDs", context.Background())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 log lines, got %q", buf.String())
+	}
+	for _, line := range lines[:2] {
+		if !strings.Contains(line, `"request_id":"req-1"`) || !strings.Contains(line, `"correlation_id":"corr-1"`) {
+			t.Errorf("Expected both IDs, got %s", line)
+		}
+	}
+	if strings.Contains(lines[2], "request_id") {
+		t.Errorf("Expected no request ID, got %s", lines[2])
+	}
+}
+
+func TestEnvelopeRequestID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(ContextWithRequestID(r.Context(), "req-42"))
+
+	w := httptest.NewRecorder()
+	NewOKFor(w, r, Send())
+
+	val, err := ExtractResponseBody[NewResponse](w.Result())
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if val.RequestID() != "req-42" {
+		t.Errorf("Expected request_id req-42, got %s", w.Body.String())
+	}
+
+	t.Run("WithoutID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewOKFor(w, httptest.NewRequest(http.MethodGet, "/", nil), Send())
+		if strings.Contains(w.Body.String(), RequestIDField) {
+			t.Errorf("Expected no request_id field, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Omitted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewOKFor(w, r, WithEnvelopeSchema(EnvelopeSchema{RequestIDField: OmitField}), Send())
+		if strings.Contains(w.Body.String(), "req-42") {
+			t.Errorf("Expected the omitted field to be left out, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Problem", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewErrFor(w, r, WithErrorFormat(ErrorFormatProblem), Send())
+		if !strings.Contains(w.Body.String(), `"request_id":"req-42"`) {
+			t.Errorf("Expected a request_id extension, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("StableETag", func(t *testing.T) {
+		etags := make([]string, 2)
+		for i, id := range []string{"req-1", "req-2"} {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(ContextWithRequestID(r.Context(), id))
+			w := httptest.NewRecorder()
+			NewOKFor(w, r, WithData("same"), WithAutoETag(false), Send())
+			etags[i] = w.Header().Get("ETag")
+		}
+		if etags[0] == "" || etags[0] != etags[1] {
+			t.Errorf("Expected the ETag to ignore the request ID, got %v", etags)
+		}
+	})
+}
@@ -6,12 +6,21 @@ import (
 
 // Response represents an HTTP response that can be modified before sending
 type Response struct {
-	w       http.ResponseWriter
-	status  int
-	success bool
-	message string
-	data    any
-	headers map[string]string
+	w           http.ResponseWriter
+	req         *http.Request
+	status      int
+	success     bool
+	message     string
+	data        any
+	meta        map[string]any
+	headers     http.Header
+	problem     problemOptions
+	schema      *EnvelopeSchema
+	page        *Page
+	conditional conditionalOptions
+	errorDetail errorOptions
+	state       ResponseState
+	err         error
 }
 
 // ResponseOption is a function that configures a response
@@ -19,13 +28,21 @@ type ResponseOption func(*responseConfig)
 
 // responseConfig holds the configuration for a response
 type responseConfig struct {
-	w       http.ResponseWriter
-	status  int
-	success bool
-	message string
-	data    any
-	send    bool
-	headers map[string]string
+	w           http.ResponseWriter
+	req         *http.Request
+	status      int
+	success     bool
+	message     string
+	data        any
+	meta        map[string]any
+	send        bool
+	headers     http.Header
+	problem     problemOptions
+	schema      *EnvelopeSchema
+	page        *Page
+	stream      streamOptions
+	conditional conditionalOptions
+	errorDetail errorOptions
 }
 
 // WithData sets the response data
@@ -35,6 +52,16 @@ func WithData(data any) ResponseOption {
 	}
 }
 
+// WithMeta adds a field to the meta block of the envelope
+func WithMeta(key string, value any) ResponseOption {
+	return func(rc *responseConfig) {
+		if rc.meta == nil {
+			rc.meta = make(map[string]any)
+		}
+		rc.meta[key] = value
+	}
+}
+
 // WithMessage sets the response message
 func WithMessage(message string) ResponseOption {
 	return func(rc *responseConfig) {
@@ -49,22 +76,38 @@ func WithStatus(status int) ResponseOption {
 	}
 }
 
-// WithHeader adds a header to the response
+// WithHeader sets a header on the response, replacing earlier values
 func WithHeader(key, value string) ResponseOption {
 	return func(rc *responseConfig) {
-		rc.headers[key] = value
+		rc.headers.Set(key, value)
 	}
 }
 
-// WithHeaders adds multiple headers to the response
+// WithHeaders sets multiple headers on the response
 func WithHeaders(headers map[string]string) ResponseOption {
 	return func(rc *responseConfig) {
 		for key, value := range headers {
-			rc.headers[key] = value
+			rc.headers.Set(key, value)
 		}
 	}
 }
 
+// WithCookie adds a Set-Cookie header to the response, invalid cookies are dropped
+func WithCookie(cookie *http.Cookie) ResponseOption {
+	return func(rc *responseConfig) {
+		if v := cookie.String(); v != "" {
+			rc.headers.Add("Set-Cookie", v)
+		}
+	}
+}
+
+// WithRequest attaches the request, used for content negotiation
+func WithRequest(r *http.Request) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.req = r
+	}
+}
+
 // Send marks the response to be sent immediately
 func Send() ResponseOption {
 	return func(rc *responseConfig) {
@@ -74,8 +117,8 @@ func Send() ResponseOption {
 
 // SetMessage sets the response message
 func (r *Response) SetMessage(message string) *Response {
-	if r == nil {
-		return nil
+	if r == nil || !r.modifiable() {
+		return r
 	}
 	r.message = message
 	return r
@@ -83,8 +126,8 @@ func (r *Response) SetMessage(message string) *Response {
 
 // SetStatus sets the HTTP status code
 func (r *Response) SetStatus(status int) *Response {
-	if r == nil {
-		return nil
+	if r == nil || !r.modifiable() {
+		return r
 	}
 	r.status = status
 	return r
@@ -92,18 +135,37 @@ func (r *Response) SetStatus(status int) *Response {
 
 // SetData replaces the entire response data
 func (r *Response) SetData(data any) *Response {
-	if r == nil {
-		return nil
+	if r == nil || !r.modifiable() {
+		return r
 	}
 	r.data = data
 	return r
 }
 
+// SetHeader sets a header on the response, replacing earlier values
+func (r *Response) SetHeader(key, value string) *Response {
+	if r == nil || !r.modifiable() {
+		return r
+	}
+	r.headers.Set(key, value)
+	return r
+}
+
+// AddHeader adds a value to a header of the response, keeping earlier values
+// Example: resp.AddHeader("Vary", "Origin").AddHeader("Vary", "Cookie")
+func (r *Response) AddHeader(key, value string) *Response {
+	if r == nil || !r.modifiable() {
+		return r
+	}
+	r.headers.Add(key, value)
+	return r
+}
+
 // AddData adds a single key-value pair to the response data
 // If data is not a map, it will be converted to one
 func (r *Response) AddData(key string, value any) *Response {
-	if r == nil {
-		return nil
+	if r == nil || !r.modifiable() {
+		return r
 	}
 
 	// Ensure data is a map
@@ -140,12 +202,70 @@ func (r *Response) AddData(key string, value any) *Response {
 }
 
 // Send writes the response to the HTTP response writer
+// A response is sent at most once, later calls return ErrAlreadySent or the error of the failed send
+// ErrHeadersCommitted is returned when the writer has already written its headers
 func (r *Response) Send() error {
 	if r == nil {
 		return nil
 	}
+	switch r.state {
+	case StateSent:
+		return ErrAlreadySent
+	case StateFailed:
+		return r.err
+	}
+
+	// The attached error is logged even when the response cannot be written
+	r.reportError()
+
+	if HeadersWritten(r.w) {
+		return r.fail(ErrHeadersCommitted)
+	}
+
+	if r.page != nil && r.req != nil {
+		if link := r.page.linkHeader(r.req); link != "" {
+			r.headers.Set("Link", link)
+		}
+	}
+
+	doc := r.document()
+	var err error
+	if r.conditional.enabled() {
+		err = r.sendConditional(doc)
+	} else {
+		err = writeResponse(r.w, r.req, r.status, r.headers, doc)
+	}
+	if err != nil {
+		return r.fail(err)
+	}
 
-	return writeJSON(r.w, r.status, r.success, r.message, r.headers, r.data)
+	r.state = StateSent
+	return nil
+}
+
+// document builds the body of the response, a problem document or the standard envelope
+func (r *Response) document() Document {
+	if !r.success && r.problem.enabled() {
+		problem := newProblemDetails(r.status, r.message, r.data, r.problem)
+		if len(r.meta) > 0 {
+			problem.Extensions["meta"] = r.meta
+		}
+		if id := requestIDOf(r.req); id != "" {
+			problem.Extensions[RequestIDField] = id
+		}
+		return problem
+	}
+
+	return NewResponse{
+		status:    r.status,
+		success:   r.success,
+		message:   r.message,
+		data:      r.data,
+		meta:      r.meta,
+		requestID: requestIDOf(r.req),
+		timestamp: getTimestamp(),
+		schema:    r.schema,
+	}
 }
 
 // buildResponse applies all options and returns a Response object
@@ -159,7 +279,7 @@ func buildResponse(w http.ResponseWriter, defaultStatus int, isError bool, defau
 		message: defaultMessage,
 		data:    nil,
 		send:    false,
-		headers: make(map[string]string),
+		headers: make(http.Header),
 	}
 
 	// Apply all options
@@ -169,20 +289,29 @@ func buildResponse(w http.ResponseWriter, defaultStatus int, isError bool, defau
 
 	// Create Response object
 	resp := &Response{
-		w:       config.w,
-		status:  config.status,
-		success: config.success,
-		message: config.message,
-		data:    nil,
-		headers: config.headers,
+		w:           config.w,
+		req:         config.req,
+		status:      config.status,
+		success:     config.success,
+		message:     config.message,
+		data:        nil,
+		headers:     config.headers,
+		meta:        config.meta,
+		problem:     config.problem,
+		schema:      config.schema,
+		page:        config.page,
+		conditional: config.conditional,
+		errorDetail: config.errorDetail,
 	}
 
 	// Set data as-is
 	resp.data = config.data
 
-	// Auto-send if Send() option was provided
+	// Auto-sent responses are not returned, so their errors are logged
 	if config.send {
-		resp.Send()
+		if err := resp.Send(); err != nil {
+			DefaultLogger().Error("Failed to send response", Field("status", resp.status), Field("error", err.Error()))
+		}
 		return nil
 	}
 
@@ -191,10 +320,20 @@ func buildResponse(w http.ResponseWriter, defaultStatus int, isError bool, defau
 
 // NewOK creates a success response with options
 func NewOK(w http.ResponseWriter, opts ...ResponseOption) *Response {
-	return buildResponse(w, http.StatusOK, false, "Success", opts)
+	return buildResponse(w, http.StatusOK, false, SuccessMessage, opts)
 }
 
 // NewErr creates an error response with options
 func NewErr(w http.ResponseWriter, opts ...ResponseOption) *Response {
-	return buildResponse(w, http.StatusInternalServerError, true, "Internal server error", opts)
+	return buildResponse(w, http.StatusInternalServerError, true, InternalServerErrorMessage, opts)
+}
+
+// NewOKFor creates a success response that negotiates its encoding with the request
+func NewOKFor(w http.ResponseWriter, r *http.Request, opts ...ResponseOption) *Response {
+	return NewOK(w, append([]ResponseOption{WithRequest(r)}, opts...)...)
+}
+
+// NewErrFor creates an error response that negotiates its encoding with the request
+func NewErrFor(w http.ResponseWriter, r *http.Request, opts ...ResponseOption) *Response {
+	return NewErr(w, append([]ResponseOption{WithRequest(r)}, opts...)...)
 }
@@ -0,0 +1,406 @@
+package utils
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in the names of rotated files, e.g. app-2024-05-01T10-00-00.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// ErrFileClosed is returned when writing to a closed RotatingFile
+var ErrFileClosed = errors.New("gecho: rotating file closed")
+
+// RotateOption is a function that configures a RotatingFile
+type RotateOption func(*RotatingFile)
+
+// WithMaxSize rotates the file before a write would make it larger than size bytes
+func WithMaxSize(size int64) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.maxSize = size
+	}
+}
+
+// WithRotateEvery rotates the file at every multiple of interval since local midnight, e.g. 24*time.Hour for daily files
+// Boundaries follow the wall clock of the local time zone, so daily files start at midnight across daylight saving changes
+// Intervals longer than a day are counted from the midnight of the day the file was opened or last rotated
+func WithRotateEvery(interval time.Duration) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.interval = interval
+	}
+}
+
+// WithMaxBackups keeps at most n rotated files, zero keeps them all
+func WithMaxBackups(n int) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.maxBackups = n
+	}
+}
+
+// WithMaxAge removes rotated files older than age, zero keeps them regardless of age
+func WithMaxAge(age time.Duration) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.maxAge = age
+	}
+}
+
+// WithCompress gzips rotated files in the background
+func WithCompress(compress bool) RotateOption {
+	return func(rf *RotatingFile) {
+		rf.compress = compress
+	}
+}
+
+// WithReopenOnSIGHUP reopens the file when the process receives SIGHUP, for use with external rotation tools
+func WithReopenOnSIGHUP() RotateOption {
+	return func(rf *RotatingFile) {
+		rf.sighup = true
+	}
+}
+
+// RotatingFile is an io.Writer appending to a file that is rotated by size and/or time
+// Rotated files are renamed with a timestamp, e.g. app-2024-05-01T10-00-00.000.log, optionally gzipped,
+// and removed beyond the maximum number of backups or age
+// It is safe for concurrent use, so it can be shared by loggers derived with WithFields
+// Example: out, err := utils.NewRotatingFile("logs/app.log", utils.WithMaxSize(100<<20), utils.WithMaxBackups(7))
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	size       int64
+	nextRotate time.Time
+	closed     bool
+
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	sighup     bool
+
+	now     func() time.Time
+	millMu  sync.Mutex     // Serializes compression and cleanup of backups
+	milling sync.WaitGroup // Background compression and cleanup, waited for by Close
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// NewRotatingFile opens or creates the file at path, creating its directory when needed
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		path: path,
+		now:  time.Now,
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(rf)
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	if rf.sighup {
+		rf.signals = make(chan os.Signal, 1)
+		signal.Notify(rf.signals, syscall.SIGHUP)
+		go rf.watchSignals()
+	}
+
+	return rf, nil
+}
+
+// Write appends p to the file, rotating it first when it is due
+// When the rotation fails p is still appended to the current file, the error is returned with the bytes written
+// and the rotation is tried again on a later write
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return 0, ErrFileClosed
+	}
+	if rf.file == nil {
+		// An earlier rotation or reopen could not open the file again
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	var rotateErr error
+	if rf.due(int64(len(p))) {
+		if rf.size == 0 {
+			// Nothing was written during the interval, there is nothing to keep
+			rf.nextRotate = rf.nextBoundary(rf.now())
+		} else if rotateErr = rf.rotate(); rf.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, errors.Join(rotateErr, err)
+}
+
+// Rotate renames the current file to a backup and starts a new one
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return ErrFileClosed
+	}
+	return rf.rotate()
+}
+
+// Reopen closes and reopens the file at its path, without renaming it
+// Use it after an external tool has moved the file, it is called on SIGHUP with WithReopenOnSIGHUP
+func (rf *RotatingFile) Reopen() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return ErrFileClosed
+	}
+	return errors.Join(rf.closeFile(), rf.open())
+}
+
+// Close closes the file and waits for background compression and cleanup to finish
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	if rf.closed {
+		rf.mu.Unlock()
+		return nil
+	}
+	rf.closed = true
+	if rf.signals != nil {
+		signal.Stop(rf.signals)
+	}
+	close(rf.done)
+	err := rf.closeFile()
+	rf.mu.Unlock()
+
+	rf.milling.Wait()
+	return err
+}
+
+// watchSignals reopens the file on SIGHUP until the file is closed
+func (rf *RotatingFile) watchSignals() {
+	for {
+		select {
+		case <-rf.signals:
+			rf.Reopen()
+		case <-rf.done:
+			return
+		}
+	}
+}
+
+// due reports whether the file must be rotated before writing n bytes
+func (rf *RotatingFile) due(n int64) bool {
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+n > rf.maxSize {
+		return true
+	}
+	return rf.interval > 0 && !rf.now().Before(rf.nextRotate)
+}
+
+// open opens the file for appending, the caller must hold rf.mu
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	if rf.interval > 0 {
+		rf.nextRotate = rf.nextBoundary(rf.now())
+	}
+	return nil
+}
+
+// closeFile closes the file, which stays nil until open succeeds, the caller must hold rf.mu
+func (rf *RotatingFile) closeFile() error {
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// nextBoundary returns the first multiple of the interval after t, counted in wall clock time from the midnight of t
+func (rf *RotatingFile) nextBoundary(t time.Time) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	sinceMidnight := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+
+	// time.Date normalizes the nanoseconds past the end of the day on the wall clock
+	next := (sinceMidnight/rf.interval + 1) * rf.interval
+	return time.Date(year, month, day, 0, 0, 0, int(next), t.Location())
+}
+
+// rotate renames the file to a backup and opens a new one, the caller must hold rf.mu
+// When the rotation fails the current file is opened again, so later writes are not lost
+// When that fails too the file stays closed and Write tries to open it again
+func (rf *RotatingFile) rotate() error {
+	if err := rf.closeFile(); err != nil {
+		return errors.Join(fmt.Errorf("gecho: rotating %s: %w", rf.path, err), rf.open())
+	}
+
+	// Rotations within the same millisecond get distinct names
+	backup := rf.backupName(rf.now())
+	for t := rf.now(); fileExists(backup) || fileExists(backup+".gz"); {
+		t = t.Add(time.Millisecond)
+		backup = rf.backupName(t)
+	}
+	if err := os.Rename(rf.path, backup); err != nil && !os.IsNotExist(err) {
+		return errors.Join(fmt.Errorf("gecho: rotating %s: %w", rf.path, err), rf.open())
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	rf.milling.Add(1)
+	go func() {
+		defer rf.milling.Done()
+		rf.mill(backup)
+	}()
+	return nil
+}
+
+// backupName returns the name of a backup rotated at t
+func (rf *RotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(rf.path)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, name+"-"+t.Format(backupTimeFormat)+ext)
+}
+
+// fileExists reports whether a file exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// backup is a rotated file and the time it was rotated
+type backup struct {
+	path      string
+	rotatedAt time.Time
+}
+
+// backups returns the rotated files of the path, newest first
+func (rf *RotatingFile) backups() ([]backup, error) {
+	dir, base := filepath.Split(rf.path)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		stamp, ok := strings.CutSuffix(stamp, ext)
+		if !ok {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		found = append(found, backup{path: filepath.Join(dir, name), rotatedAt: t})
+	}
+
+	slices.SortFunc(found, func(a, b backup) int {
+		return b.rotatedAt.Compare(a.rotatedAt)
+	})
+	return found, nil
+}
+
+// mill compresses a new backup and removes the backups beyond the maximum count or age
+func (rf *RotatingFile) mill(rotated string) {
+	rf.millMu.Lock()
+	defer rf.millMu.Unlock()
+
+	if rf.compress {
+		compressFile(rotated)
+	}
+
+	if rf.maxBackups <= 0 && rf.maxAge <= 0 {
+		return
+	}
+
+	backups, err := rf.backups()
+	if err != nil {
+		return
+	}
+	cutoff := rf.now().Add(-rf.maxAge)
+	for i, b := range backups {
+		tooMany := rf.maxBackups > 0 && i >= rf.maxBackups
+		tooOld := rf.maxAge > 0 && b.rotatedAt.Before(cutoff)
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,349 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for rotation tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// openRotating opens a rotating file in a temporary directory using the clock
+func openRotating(t *testing.T, clock *fakeClock, opts ...RotateOption) (*RotatingFile, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if clock != nil {
+		opts = append([]RotateOption{func(rf *RotatingFile) { rf.now = clock.Now }}, opts...)
+	}
+	rf, err := NewRotatingFile(path, opts...)
+	if err != nil {
+		t.Fatalf("Expected the file to open, got %v", err)
+	}
+	t.Cleanup(func() { rf.Close() })
+	return rf, dir
+}
+
+// listBackups returns the names of the files in dir other than app.log
+func listBackups(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)}
+	rf, dir := openRotating(t, clock, WithMaxSize(10))
+
+	rf.Write([]byte("12345678\n"))
+	clock.Advance(time.Second)
+	rf.Write([]byte("abcdefgh\n"))
+	rf.Close()
+
+	backups := listBackups(t, dir)
+	if len(backups) != 1 || backups[0] != "app-2024-05-01T10-00-01.000.log" {
+		t.Fatalf("Expected one timestamped backup, got %v", backups)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, backups[0])); string(data) != "12345678\n" {
+		t.Errorf("Expected the first write in the backup, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "app.log")); string(data) != "abcdefgh\n" {
+		t.Errorf("Expected the second write in the new file, got %q", data)
+	}
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 30, 0, 0, time.Local)}
+	rf, dir := openRotating(t, clock, WithRotateEvery(time.Hour))
+
+	rf.Write([]byte("first\n"))
+	clock.Advance(20 * time.Minute)
+	rf.Write([]byte("same hour\n"))
+	if backups := listBackups(t, dir); len(backups) != 0 {
+		t.Fatalf("Expected no rotation within the hour, got %v", backups)
+	}
+
+	clock.Advance(20 * time.Minute)
+	rf.Write([]byte("next hour\n"))
+	if backups := listBackups(t, dir); len(backups) != 1 {
+		t.Fatalf("Expected a rotation at the hour, got %v", backups)
+	}
+
+	// An idle interval does not produce an empty backup
+	rf.Rotate()
+	clock.Advance(2 * time.Hour)
+	rf.Write([]byte("after idle\n"))
+	if backups := listBackups(t, dir); len(backups) != 2 {
+		t.Errorf("Expected no backup of the empty file, got %v", backups)
+	}
+}
+
+func TestRotatingFileLocalBoundaries(t *testing.T) {
+	zone := time.FixedZone("UTC+5:30", 5*3600+1800)
+	tests := []struct {
+		name     string
+		interval time.Duration
+		now      time.Time
+		expected time.Time
+	}{
+		{"Daily", 24 * time.Hour, time.Date(2024, 5, 1, 23, 59, 0, 0, zone), time.Date(2024, 5, 2, 0, 0, 0, 0, zone)},
+		{"Hourly", time.Hour, time.Date(2024, 5, 1, 10, 30, 0, 0, zone), time.Date(2024, 5, 1, 11, 0, 0, 0, zone)},
+		{"OnBoundary", 6 * time.Hour, time.Date(2024, 5, 1, 12, 0, 0, 0, zone), time.Date(2024, 5, 1, 18, 0, 0, 0, zone)},
+		{"LongerThanADay", 48 * time.Hour, time.Date(2024, 5, 1, 8, 0, 0, 0, zone), time.Date(2024, 5, 3, 0, 0, 0, 0, zone)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rf := &RotatingFile{interval: tt.interval}
+			if next := rf.nextBoundary(tt.now); !next.Equal(tt.expected) {
+				t.Errorf("Expected the next rotation at %v, got %v", tt.expected, next)
+			}
+		})
+	}
+
+	t.Run("DaylightSaving", func(t *testing.T) {
+		amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+		if err != nil {
+			t.Skip("time zone database not available")
+		}
+		// The day the clocks go forward is 23 hours long
+		rf := &RotatingFile{interval: 24 * time.Hour}
+		next := rf.nextBoundary(time.Date(2024, 3, 31, 1, 0, 0, 0, amsterdam))
+		if expected := time.Date(2024, 4, 1, 0, 0, 0, 0, amsterdam); !next.Equal(expected) {
+			t.Errorf("Expected the next rotation at %v, got %v", expected, next)
+		}
+	})
+}
+
+func TestRotatingFileFailedRotationKeepsEntry(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)}
+	rf, dir := openRotating(t, clock, WithMaxSize(10))
+	rf.Write([]byte("12345678\n"))
+
+	// Closing the file behind its back makes the next rotation fail
+	rf.file.Close()
+	n, err := rf.Write([]byte("abcdefgh\n"))
+	if err == nil || n != 9 {
+		t.Fatalf("Expected the entry to be written with the rotation error, got %d %v", n, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "app.log")); string(data) != "12345678\nabcdefgh\n" {
+		t.Errorf("Expected the entry in the current file, got %q", data)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := rf.Write([]byte("next\n")); err != nil {
+		t.Fatalf("Expected the rotation to be retried, got %v", err)
+	}
+	if backups := listBackups(t, dir); len(backups) != 1 {
+		t.Errorf("Expected a backup after the retried rotation, got %v", backups)
+	}
+}
+
+func TestRotatingFileRecovers(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+	rf, err := NewRotatingFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	rf.Write([]byte("before\n"))
+
+	// A file in place of the directory makes both the rename and the reopen fail
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Rotate(); err == nil {
+		t.Fatal("Expected the rotation to fail")
+	}
+	if _, err := rf.Write([]byte("lost\n")); err == nil || errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected the error opening the file, got %v", err)
+	}
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rf.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Expected the file to be opened again, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "after\n" {
+		t.Errorf("Expected the write in the reopened file, got %q", data)
+	}
+}
+
+func TestRotatingFileRetention(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)}
+	rf, dir := openRotating(t, clock, WithMaxBackups(2), WithMaxAge(time.Hour))
+
+	for range 4 {
+		rf.Write([]byte("entry\n"))
+		rf.Rotate()
+		clock.Advance(time.Minute)
+	}
+	rf.Close()
+
+	backups := listBackups(t, dir)
+	expected := []string{"app-2024-05-01T10-02-00.000.log", "app-2024-05-01T10-03-00.000.log"}
+	if strings.Join(backups, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected the two newest backups, got %v", backups)
+	}
+
+	rf, dir = openRotating(t, clock, WithMaxAge(time.Hour))
+	rf.Write([]byte("old\n"))
+	rf.Rotate()
+	clock.Advance(2 * time.Hour)
+	rf.Write([]byte("new\n"))
+	rf.Rotate()
+	rf.Close()
+
+	if backups := listBackups(t, dir); len(backups) != 1 || !strings.Contains(backups[0], "T12-04-00") {
+		t.Errorf("Expected the old backup to be removed, got %v", backups)
+	}
+}
+
+func TestRotatingFileSameInstant(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)}
+	rf, dir := openRotating(t, clock)
+
+	rf.Write([]byte("one\n"))
+	rf.Rotate()
+	rf.Write([]byte("two\n"))
+	rf.Rotate()
+
+	if backups := listBackups(t, dir); len(backups) != 2 {
+		t.Errorf("Expected distinct backups for rotations at the same instant, got %v", backups)
+	}
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	rf, dir := openRotating(t, nil, WithCompress(true))
+
+	rf.Write([]byte("compressed entry\n"))
+	if err := rf.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	rf.Close()
+
+	backups := listBackups(t, dir)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("Expected only the gzipped backup, got %v", backups)
+	}
+
+	file, err := os.Open(filepath.Join(dir, backups[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(gz); string(data) != "compressed entry\n" {
+		t.Errorf("Expected the entry in the gzipped backup, got %q", data)
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	rf, dir := openRotating(t, nil)
+	path := filepath.Join(dir, "app.log")
+
+	rf.Write([]byte("before\n"))
+	if err := os.Rename(path, filepath.Join(dir, "moved.log")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	rf.Write([]byte("after\n"))
+
+	if data, _ := os.ReadFile(path); string(data) != "after\n" {
+		t.Errorf("Expected writes to go to the reopened file, got %q", data)
+	}
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	rf, _ := openRotating(t, nil)
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rf.Write([]byte("late\n")); !errors.Is(err, ErrFileClosed) {
+		t.Errorf("Expected ErrFileClosed, got %v", err)
+	}
+	if err := rf.Close(); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+}
+
+func TestRotatingFileSharedByLoggers(t *testing.T) {
+	rf, dir := openRotating(t, nil, WithMaxSize(4096))
+	logger := NewLogger(NewConfig(WithOutput(rf), WithLogFormat(FormatJSON)))
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := logger.WithField("worker", worker)
+			for range 50 {
+				child.Info("Processed")
+			}
+		}()
+	}
+	wg.Wait()
+	rf.Close()
+
+	var all bytes.Buffer
+	for _, name := range append(listBackups(t, dir), "app.log") {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		all.Write(data)
+	}
+
+	lines := strings.Split(strings.TrimSpace(all.String()), "\n")
+	if len(lines) != 400 {
+		t.Fatalf("Expected 400 entries across the files, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+			t.Fatalf("Expected whole entries, got %q", line)
+		}
+	}
+}
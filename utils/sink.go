@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"io"
+)
+
+// SinkFilter reports whether a sink writes an entry, given its level, message and fields
+type SinkFilter func(level Level, msg string, fields map[string]any) bool
+
+// Sink is one destination of a logger, with its own level, format and filter
+// Sinks with a QueueSize are written from their own goroutine, so a slow or stalled sink does not hold up the others
+// Example: utils.Sink{Output: conn, Level: utils.LevelWarn, Format: utils.FormatJSON, QueueSize: 1024, Overflow: utils.OverflowDropOldest}
+type Sink struct {
+	Output    io.Writer
+	Level     Level          // Minimum level written to the sink, on top of the level of the logger
+	Format    Format         // Format of the entries written to the sink
+	Colorize  bool           // Colors the text and pretty formats
+	Formatter Formatter      // Custom format replacing Format and Colorize when set
+	Filter    SinkFilter     // Optional predicate an entry must pass to be written
+	QueueSize int            // Entries buffered for the sink, zero writes synchronously
+	Overflow  OverflowPolicy // What happens when the queue of the sink is full
+}
+
+// WithSinks adds sinks to the logger, Output and ErrorOutput are ignored once a sink is set
+// The level of the logger still applies first, set it to the lowest level of the sinks
+func WithSinks(sinks ...Sink) LoggerOptions {
+	return func(c *Config) {
+		c.Sinks = append(c.Sinks, sinks...)
+	}
+}
+
+// sinkWriter is a sink of a logger, shared with the loggers derived from it
+type sinkWriter struct {
+	Sink
+	w     io.Writer   // Output, or a writer queueing to the sink goroutine
+	queue *asyncQueue // Queue of the sink, nil when it is written synchronously
+}
+
+// newSinkWriters creates the writers of the sinks, starting the goroutines of queued sinks
+func newSinkWriters(sinks []Sink, timeFormat string) []*sinkWriter {
+	writers := make([]*sinkWriter, 0, len(sinks))
+	for _, sink := range sinks {
+		if sink.Output == nil {
+			continue
+		}
+		sw := &sinkWriter{Sink: sink, w: sink.Output}
+		if sink.QueueSize > 0 {
+			sw.queue = newAsyncQueue(sink.QueueSize, sink.Overflow, func(dropped uint64) {
+				sw.reportDropped(dropped, timeFormat)
+			})
+			sw.w = queueWriter{queue: sw.queue, w: sink.Output}
+		}
+		writers = append(writers, sw)
+	}
+	return writers
+}
+
+// formatter returns the Formatter of the sink, or the built-in one of its Format
+func (sw *sinkWriter) formatter() Formatter {
+	if sw.Formatter != nil {
+		return sw.Formatter
+	}
+	return FormatterFor(sw.Format, sw.Colorize)
+}
+
+// accepts reports whether the sink writes the entry
+func (sw *sinkWriter) accepts(e *Entry) bool {
+	if e.Level < sw.Level {
+		return false
+	}
+	if sw.Filter == nil {
+		return true
+	}
+	fields := make(map[string]any, len(e.Fields))
+	for _, f := range e.Fields {
+		fields[f.Key] = f.Value
+	}
+	return sw.Filter(e.Level, e.Message, fields)
+}
+
+// reportDropped writes a warning with the number of entries the sink dropped, bypassing its queue
+func (sw *sinkWriter) reportDropped(dropped uint64, timeFormat string) {
+	e := droppedEntry(dropped, sw.Overflow, timeFormat)
+	sw.Output.Write(formatEntry(sw.formatter(), e))
+}
+
+// queueWriter queues the entries written to it, writing them itself once the queue is closed
+// Entries are formatted for each write, so p is not reused and needs no copy
+type queueWriter struct {
+	queue *asyncQueue
+	w     io.Writer
+}
+
+// Write queues p for the sink goroutine
+func (qw queueWriter) Write(p []byte) (int, error) {
+	if !qw.queue.enqueue(asyncRecord{w: qw.w, data: p}) {
+		return qw.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// writeSinks formats the entry for every sink accepting it
+// A sink failing to write does not prevent the others from receiving the entry
+func (l *Logger) writeSinks(e *Entry) {
+	for _, sw := range l.sinks {
+		if sw.accepts(e) {
+			l.emit(sw.w, formatEntry(sw.formatter(), e))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestSinks(t *testing.T) {
+	var console, file, alerts bytes.Buffer
+	logger := NewLogger(NewConfig(
+		WithLogLevel(LevelDebug),
+		WithShowCaller(false),
+		WithSinks(
+			Sink{Output: &console, Level: LevelDebug, Format: FormatPretty, Colorize: true},
+			Sink{Output: &file, Level: LevelInfo, Format: FormatJSON},
+			Sink{Output: &alerts, Level: LevelWarn, Format: FormatText, Filter: func(level Level, msg string, fields map[string]any) bool {
+				return fields["noisy"] != true
+			}},
+		),
+	))
+
+	logger.Debug("Cache miss")
+	logger.WithField("component", "db").Info("Connected")
+	logger.Warn("Slow query", Field("noisy", true))
+	logger.Error("Query failed")
+
+	if strings.Count(console.String(), "\n") != 4 || !strings.Contains(console.String(), "\033[36mDEBUG") {
+		t.Errorf("Expected every entry colored on the console, got %q", console.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected the info and above entries in the file, got %q", file.String())
+	}
+	var connected map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &connected); err != nil || connected["message"] != "Connected" {
+		t.Errorf("Expected JSON entries in the file, got %s", lines[0])
+	}
+
+	if strings.Contains(alerts.String(), "Slow query") || !strings.Contains(alerts.String(), "ERROR Query failed") {
+		t.Errorf("Expected only the unfiltered error in the alerts, got %q", alerts.String())
+	}
+}
+
+func TestSinkFailureIsolated(t *testing.T) {
+	var file bytes.Buffer
+	network := newGatedWriter()
+	logger := NewLogger(NewConfig(
+		WithShowCaller(false),
+		WithSinks(
+			Sink{Output: failingWriter{}},
+			Sink{Output: network, QueueSize: 10},
+			Sink{Output: &file},
+		),
+	))
+	defer logger.Close()
+
+	logger.Info("first")
+	<-network.entered
+	logger.WithField("child", true).Info("second")
+
+	if !strings.Contains(file.String(), "first") || !strings.Contains(file.String(), "second") {
+		t.Errorf("Expected entries while the other sinks fail or stall, got %q", file.String())
+	}
+
+	close(network.gate)
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(network.String(), "first") || !strings.Contains(network.String(), "second") {
+		t.Errorf("Expected the queued sink to catch up, got %q", network.String())
+	}
+}
+
+func TestSinkDropped(t *testing.T) {
+	network := newGatedWriter()
+	logger := NewLogger(NewConfig(
+		WithShowCaller(false),
+		WithSinks(Sink{Output: network, Format: FormatJSON, QueueSize: 1, Overflow: OverflowDropNewest}),
+	))
+	defer logger.Close()
+
+	logger.Info("entry-1")
+	<-network.entered
+	logger.Info("entry-2")
+	logger.Info("entry-3")
+
+	close(network.gate)
+	logger.Flush(context.Background())
+	if !strings.Contains(network.String(), `"message":"Log entries dropped","fields":{"dropped":1,"policy":"drop_newest"}`) {
+		t.Errorf("Expected the sink to report its dropped entry, got %q", network.String())
+	}
+	if logger.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped entry, got %d", logger.Dropped())
+	}
+}
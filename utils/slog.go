@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LevelFromSlog converts a slog level to the closest gecho level
+// Levels above slog.LevelError map to LevelError, slog records never exit the program
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// SlogLevel converts a gecho level to its slog level, LevelFatal is four above slog.LevelError
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// slogHandler is a slog.Handler writing through a gecho Logger
+type slogHandler struct {
+	logger *Logger
+	fields []FieldPair // Fields added with WithAttrs, already prefixed with their groups
+	groups []string    // Open groups, prefixing the keys of later attributes
+}
+
+// SlogHandler returns a slog.Handler that writes records through the logger and its format
+// Attributes become fields, with keys of grouped attributes joined by dots such as "http.status"
+// Example: slog.SetDefault(slog.New(logger.SlogHandler()))
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// Enabled reports whether the logger writes records of the level
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return LevelFromSlog(level) >= h.logger.Level()
+}
+
+// Handle writes the record, with the caller and time of the record and the fields of its context
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := slices.Clone(h.fields)
+	prefix := groupPrefix(h.groups)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, prefix, attr)
+		return true
+	})
+
+	opts := []Option{func(o *entryOptions) {
+		o.fields = append(o.fields, fields...)
+		o.fromRecord = true
+		o.pc = record.PC
+		o.time = record.Time
+	}}
+	if ctx == nil || ctx == context.Background() {
+		ctx = h.logger.ctx
+	}
+	h.logger.log(LevelFromSlog(record.Level), ctx, record.Message, opts)
+	return nil
+}
+
+// WithAttrs returns a handler adding the attributes to every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	child := *h
+	child.fields = slices.Clone(h.fields)
+	prefix := groupPrefix(h.groups)
+	for _, attr := range attrs {
+		child.fields = appendAttr(child.fields, prefix, attr)
+	}
+	return &child
+}
+
+// WithGroup returns a handler nesting the keys of later attributes under name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.groups = append(slices.Clone(h.groups), name)
+	return &child
+}
+
+// groupPrefix returns the key prefix of attributes in the groups
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+// appendAttr appends the attribute as fields, flattening groups into dotted keys
+// Empty attributes are ignored and groups without a key are inlined, as slog requires
+func appendAttr(fields []FieldPair, prefix string, attr slog.Attr) []FieldPair {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return fields
+		}
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range members {
+			fields = appendAttr(fields, prefix, member)
+		}
+		return fields
+	}
+
+	return append(fields, FieldPair{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+// NewLoggerFromSlog creates a logger that hands its entries to a slog handler
+// Fields become attributes and the level filtering is left to the handler, so the
+// Debug, Info, Field and WithFields API of gecho can target any slog backend
+// Example: logger := utils.NewLoggerFromSlog(slog.NewJSONHandler(os.Stdout, nil))
+func NewLoggerFromSlog(handler slog.Handler) *Logger {
+	logger := NewLogger(NewConfig(WithLogLevel(LevelDebug)))
+	logger.slog = handler
+	return logger
+}
+
+// handleSlog converts an entry to a slog record and hands it to the slog handler
+func (l *Logger) handleSlog(ctx context.Context, level Level, now time.Time, msg string, pc uintptr, fields []FieldPair) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	slogLevel := SlogLevel(level)
+	if !l.slog.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	record := slog.NewRecord(now, slogLevel, msg, pc)
+	if l.name != "" {
+		record.AddAttrs(slog.String("logger", l.name))
+	}
+	for _, f := range fields {
+		record.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	l.slog.Handle(ctx, record)
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := jsonLogger(&buf)
+
+	slogger := slog.New(logger.SlogHandler()).With("service", "api").WithGroup("http")
+	slogger.Debug("Hidden")
+	slogger.Warn("Slow request", "status", 200, slog.Group("client", "ip", "10.0.0.1"), slog.Group("empty"))
+
+	var entry struct {
+		Level   string         `json:"level"`
+		Message string         `json:"message"`
+		Caller  string         `json:"caller"`
+		Fields  map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON entry, got %v (%s)", err, buf.String())
+	}
+
+	if entry.Level != "WARN" || entry.Message != "Slow request" {
+		t.Errorf("Expected the warning, got %+v", entry)
+	}
+	if !strings.HasPrefix(entry.Caller, "slog_test.go:") {
+		t.Errorf("Expected the caller of the slog call, got %q", entry.Caller)
+	}
+
+	expected := map[string]any{"service": "api", "http.status": float64(200), "http.client.ip": "10.0.0.1"}
+	if len(entry.Fields) != len(expected) {
+		t.Errorf("Expected fields %v, got %v", expected, entry.Fields)
+	}
+	for key, value := range expected {
+		if entry.Fields[key] != value {
+			t.Errorf("Expected field %s=%v, got %v", key, value, entry.Fields[key])
+		}
+	}
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	var buf bytes.Buffer
+	slogger := slog.New(jsonLogger(&buf).SlogHandler())
+
+	slogger.InfoContext(ContextWithRequestID(context.Background(), "req-9"), "With context")
+	if !strings.Contains(buf.String(), `"request_id":"req-9"`) {
+		t.Errorf("Expected the fields of the context, got %s", buf.String())
+	}
+}
+
+func TestNewLoggerFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelInfo})
+	logger := NewLoggerFromSlog(handler).WithField("component", "billing")
+
+	logger.Debug("Hidden by the handler")
+	logger.InfoCtx(ContextWithRequestID(context.Background(), "req-3"), "Invoice sent", Field("invoice", 42))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single slog record, got %v (%s)", err, buf.String())
+	}
+
+	if record["msg"] != "Invoice sent" || record["level"] != "INFO" {
+		t.Errorf("Unexpected record %v", record)
+	}
+	if record["component"] != "billing" || record["invoice"] != float64(42) || record["request_id"] != "req-3" {
+		t.Errorf("Expected fields as attributes, got %v", record)
+	}
+
+	source, _ := record["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "slog_test.go") {
+		t.Errorf("Expected the source of the gecho call, got %v", record["source"])
+	}
+}
+
+func TestSlogLevels(t *testing.T) {
+	tests := []struct {
+		slog  slog.Level
+		gecho Level
+	}{
+		{slog.LevelDebug - 4, LevelDebug},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 8, LevelError},
+	}
+	for _, tt := range tests {
+		if got := LevelFromSlog(tt.slog); got != tt.gecho {
+			t.Errorf("LevelFromSlog(%v) = %v, expected %v", tt.slog, got, tt.gecho)
+		}
+	}
+
+	if SlogLevel(LevelWarn) != slog.LevelWarn || SlogLevel(LevelFatal) != slog.LevelError+4 {
+		t.Errorf("Unexpected slog levels %v and %v", SlogLevel(LevelWarn), SlogLevel(LevelFatal))
+	}
+}
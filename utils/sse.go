@@ -0,0 +1,414 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MediaTypeEventStream is the media type of Server-Sent Events streams
+const MediaTypeEventStream = "text/event-stream"
+
+// DefaultHeartbeatInterval is the interval between heartbeat comments of an event stream
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// ErrStreamClosed is returned when sending on an event stream that has been closed
+var ErrStreamClosed = errors.New("event stream closed")
+
+// Event is a single encoded Server-Sent Event
+type Event struct {
+	ID   string // Event ID, sent back by the client in Last-Event-ID when reconnecting
+	Name string // Event name, empty for the default "message" event
+	Data []byte // Encoded envelope sent as the event data
+}
+
+// ReplayBuffer stores sent events so reconnecting clients can resume with Last-Event-ID
+type ReplayBuffer interface {
+	// Add stores an event that has been sent
+	Add(event Event)
+	// Since returns the events sent after the event with the given ID
+	// ok is false when the ID is unknown, for example because it has been evicted
+	Since(id string) (events []Event, ok bool)
+}
+
+// RingBuffer is an in-memory ReplayBuffer keeping the most recent events
+// It is safe for concurrent use and can be shared by all streams of a topic
+type RingBuffer struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewRingBuffer creates a replay buffer holding up to size events
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{events: make([]Event, size)}
+}
+
+// Add stores an event, evicting the oldest one when the buffer is full
+func (rb *RingBuffer) Add(event Event) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.events[rb.next] = event
+	rb.next = (rb.next + 1) % len(rb.events)
+	if rb.next == 0 {
+		rb.full = true
+	}
+}
+
+// Since returns the events stored after the event with the given ID, oldest first
+func (rb *RingBuffer) Since(id string) ([]Event, bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	ordered := rb.events[:rb.next]
+	if rb.full {
+		ordered = append(rb.events[rb.next:len(rb.events):len(rb.events)], rb.events[:rb.next]...)
+	}
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		if ordered[i].ID == id {
+			return append([]Event(nil), ordered[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+// EventStreamOption is a function that configures an event stream
+type EventStreamOption func(*eventStreamConfig)
+
+// eventStreamConfig holds the configuration of an event stream
+type eventStreamConfig struct {
+	heartbeat time.Duration
+	retry     time.Duration
+	replay    ReplayBuffer
+	logger    *Logger
+	schema    *EnvelopeSchema
+}
+
+// WithHeartbeat sets the interval between heartbeat comments, zero disables them
+func WithHeartbeat(interval time.Duration) EventStreamOption {
+	return func(ec *eventStreamConfig) {
+		ec.heartbeat = interval
+	}
+}
+
+// WithRetry sends a retry hint telling the client how long to wait before reconnecting
+func WithRetry(retry time.Duration) EventStreamOption {
+	return func(ec *eventStreamConfig) {
+		ec.retry = retry
+	}
+}
+
+// WithReplayBuffer stores sent events in the buffer and replays missed ones on reconnect
+func WithReplayBuffer(buffer ReplayBuffer) EventStreamOption {
+	return func(ec *eventStreamConfig) {
+		ec.replay = buffer
+	}
+}
+
+// WithEventLogger sets the logger used for the stream lifecycle, defaults to the default logger
+func WithEventLogger(logger *Logger) EventStreamOption {
+	return func(ec *eventStreamConfig) {
+		ec.logger = logger
+	}
+}
+
+// WithEventSchema encodes the event envelopes using the given schema instead of the global one
+func WithEventSchema(schema EnvelopeSchema) EventStreamOption {
+	return func(ec *eventStreamConfig) {
+		schema = schema.Normalize()
+		ec.schema = &schema
+	}
+}
+
+// EventStream writes Server-Sent Events whose data is a gecho envelope
+// It is safe for concurrent use
+type EventStream struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	req        *http.Request
+	path       string
+	config     eventStreamConfig
+	done       chan struct{}
+	closed     bool
+	sent       int
+	opened     time.Time
+}
+
+// NewEventStream starts an event stream, writing the headers and replaying the events
+// missed since the Last-Event-ID of the request when a replay buffer is set
+// The stream is closed when the client disconnects or the request ends, wait for it with Done
+// Defer Close in the handler, without a request the heartbeats only stop on Close
+// An error is returned when the writer does not support flushing or has already written its headers
+// Example: stream, err := utils.NewEventStream(w, r, utils.WithRetry(5*time.Second)); defer stream.Close()
+func NewEventStream(w http.ResponseWriter, r *http.Request, opts ...EventStreamOption) (*EventStream, error) {
+	if w == nil {
+		panic("http.ResponseWriter is nil")
+	}
+	if HeadersWritten(w) {
+		return nil, ErrHeadersCommitted
+	}
+	// Checked before any header is set, so the handler can still send an error response
+	if !canFlush(w) {
+		return nil, fmt.Errorf("event stream: %w", http.ErrNotSupported)
+	}
+
+	config := eventStreamConfig{heartbeat: DefaultHeartbeatInterval}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.logger == nil {
+		config.logger = DefaultLogger()
+	}
+
+	// A nil request is allowed, the stream then never resumes nor detects a disconnect
+	lastEventID, path := "", ""
+	if r != nil {
+		lastEventID = r.Header.Get("Last-Event-ID")
+		path = r.URL.Path
+	}
+
+	es := &EventStream{
+		w:          w,
+		controller: http.NewResponseController(w),
+		req:        r,
+		path:       path,
+		config:     config,
+		done:       make(chan struct{}),
+		opened:     time.Now(),
+	}
+
+	w.Header().Set("Content-Type", MediaTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var buf bytes.Buffer
+	if config.retry > 0 {
+		fmt.Fprintf(&buf, "retry: %d\n\n", config.retry.Milliseconds())
+	}
+
+	replayed := 0
+	if lastEventID != "" && config.replay != nil {
+		events, ok := config.replay.Since(lastEventID)
+		if !ok {
+			config.logger.Warn("Event stream cannot resume",
+				Field("path", path),
+				Field("last_event_id", lastEventID),
+			)
+		}
+		for _, event := range events {
+			writeEvent(&buf, event)
+		}
+		replayed = len(events)
+	}
+
+	if err := es.write(buf.Bytes()); err != nil {
+		config.logger.Error("Event stream failed to open", Field("path", path), Field("error", err))
+		return nil, err
+	}
+
+	config.logger.Info("Event stream opened",
+		Field("path", path),
+		Field("last_event_id", lastEventID),
+		Field("replayed", replayed),
+	)
+
+	go es.watch()
+	return es, nil
+}
+
+// canFlush reports whether w, or a writer it wraps, supports flushing as http.ResponseController does
+func canFlush(w http.ResponseWriter) bool {
+	for w != nil {
+		switch w.(type) {
+		case http.Flusher, interface{ FlushError() error }:
+			return true
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+	return false
+}
+
+// watch closes the stream when the client disconnects and sends heartbeats meanwhile
+func (es *EventStream) watch() {
+	var ticks <-chan time.Time
+	if es.config.heartbeat > 0 {
+		ticker := time.NewTicker(es.config.heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var disconnected <-chan struct{}
+	if es.req != nil {
+		disconnected = es.req.Context().Done()
+	}
+
+	for {
+		select {
+		case <-es.done:
+			return
+		case <-disconnected:
+			es.close("client disconnected")
+			return
+		case <-ticks:
+			if err := es.Heartbeat(); err != nil && !errors.Is(err, ErrStreamClosed) {
+				es.close("heartbeat failed")
+				return
+			}
+		}
+	}
+}
+
+// Send sends an event whose data is a success envelope containing data
+// An empty event name sends the default "message" event, an empty id sends no ID
+// Options such as WithMessage and WithStatus configure the envelope
+func (es *EventStream) Send(event, id string, data any, opts ...ResponseOption) error {
+	if strings.ContainsAny(event, "\r\n") || strings.ContainsAny(id, "\r\n") {
+		return fmt.Errorf("event stream: event name and id must not contain line breaks")
+	}
+
+	config := &responseConfig{
+		status:  http.StatusOK,
+		success: true,
+		message: SuccessMessage,
+		data:    data,
+		schema:  es.config.schema,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	payload, err := json.Marshal(NewResponse{
+		status:    config.status,
+		success:   config.success,
+		message:   config.message,
+		data:      config.data,
+		meta:      config.meta,
+		requestID: requestIDOf(es.req),
+		timestamp: getTimestamp(),
+		schema:    config.schema,
+	})
+	if err != nil {
+		return err
+	}
+
+	e := Event{ID: id, Name: event, Data: payload}
+
+	var buf bytes.Buffer
+	writeEvent(&buf, e)
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if es.closed {
+		return ErrStreamClosed
+	}
+	if err := es.writeLocked(buf.Bytes()); err != nil {
+		return err
+	}
+
+	es.sent++
+	if es.config.replay != nil && id != "" {
+		es.config.replay.Add(e)
+	}
+	return nil
+}
+
+// Heartbeat sends a comment that keeps idle connections and proxies alive
+func (es *EventStream) Heartbeat() error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if es.closed {
+		return ErrStreamClosed
+	}
+	return es.writeLocked([]byte(": heartbeat\n\n"))
+}
+
+// Done returns a channel that is closed when the stream is closed
+func (es *EventStream) Done() <-chan struct{} {
+	return es.done
+}
+
+// Close stops the heartbeats and marks the stream as closed, it is safe to call more than once
+func (es *EventStream) Close() error {
+	es.close("closed")
+	return nil
+}
+
+// close closes the stream once, logging the reason
+func (es *EventStream) close(reason string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.closeLocked(reason)
+}
+
+// closeLocked closes the stream once, the caller must hold the lock
+func (es *EventStream) closeLocked(reason string) {
+	if es.closed {
+		return
+	}
+	es.closed = true
+	close(es.done)
+
+	es.config.logger.Info("Event stream closed",
+		Field("path", es.path),
+		Field("reason", reason),
+		Field("events", es.sent),
+		Field("duration", time.Since(es.opened)),
+	)
+}
+
+// write writes and flushes raw output
+func (es *EventStream) write(b []byte) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.writeLocked(b)
+}
+
+// writeLocked writes and flushes raw output, the caller must hold the lock
+// Nothing is written once the request has ended, as the writer may already be finished
+func (es *EventStream) writeLocked(b []byte) error {
+	if es.req != nil && es.req.Context().Err() != nil {
+		es.closeLocked("client disconnected")
+		return ErrStreamClosed
+	}
+	if len(b) > 0 {
+		if _, err := es.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return es.controller.Flush()
+}
+
+// writeEvent encodes an event in the text/event-stream format
+func writeEvent(buf *bytes.Buffer, e Event) {
+	if e.ID != "" {
+		buf.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Name != "" {
+		buf.WriteString("event: " + e.Name + "\n")
+	}
+	for _, line := range bytes.Split(e.Data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use by the stream goroutine
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+// testEventLogger returns a JSON logger writing to a buffer
+func testEventLogger() (*Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	logger := NewLogger(NewConfig(
+		WithOutput(buf),
+		WithErrorOutput(buf),
+		WithLogFormat(FormatJSON),
+	))
+	return logger, buf
+}
+
+func TestEventStream(t *testing.T) {
+	logger, logs := testEventLogger()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/progress", nil)
+
+	stream, err := NewEventStream(w, r,
+		WithHeartbeat(0),
+		WithRetry(3*time.Second),
+		WithEventLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error on NewEventStream(), got %v", err)
+	}
+
+	if err := stream.Send("progress", "1", map[string]int{"percent": 50}); err != nil {
+		t.Fatalf("Expected no error on Send(), got %v", err)
+	}
+	if err := stream.Send("", "", nil, WithMessage("Done")); err != nil {
+		t.Fatalf("Expected no error on Send(), got %v", err)
+	}
+	stream.Close()
+
+	if ct := w.Header().Get("Content-Type"); ct != MediaTypeEventStream {
+		t.Errorf("Expected Content-Type %s, got %s", MediaTypeEventStream, ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Expected Cache-Control no-cache, got %s", cc)
+	}
+
+	events := strings.Split(strings.TrimSuffix(w.Body.String(), "\n\n"), "\n\n")
+	if len(events) != 3 {
+		t.Fatalf("Expected retry hint and 2 events, got %q", w.Body.String())
+	}
+	if events[0] != "retry: 3000" {
+		t.Errorf("Expected retry hint, got %q", events[0])
+	}
+
+	lines := strings.Split(events[1], "\n")
+	if len(lines) != 3 || lines[0] != "id: 1" || lines[1] != "event: progress" {
+		t.Fatalf("Unexpected event %q", events[1])
+	}
+
+	var nr NewResponse
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &nr); err != nil {
+		t.Fatalf("Expected an envelope as data, got %v", err)
+	}
+	if !nr.Success() || nr.Status() != http.StatusOK {
+		t.Errorf("Unexpected envelope %q", lines[2])
+	}
+	if data, ok := nr.Data().(map[string]any); !ok || data["percent"] != float64(50) {
+		t.Errorf("Expected percent 50, got %v", nr.Data())
+	}
+
+	if !strings.HasPrefix(events[2], "data: ") || !strings.Contains(events[2], `"message":"Done"`) {
+		t.Errorf("Expected a default event without id, got %q", events[2])
+	}
+
+	if !strings.Contains(logs.String(), "Event stream opened") || !strings.Contains(logs.String(), "Event stream closed") {
+		t.Errorf("Expected lifecycle logs, got %s", logs.String())
+	}
+
+	t.Run("SendAfterClose", func(t *testing.T) {
+		if err := stream.Send("progress", "2", nil); !errors.Is(err, ErrStreamClosed) {
+			t.Errorf("Expected ErrStreamClosed, got %v", err)
+		}
+	})
+
+	t.Run("InvalidEventName", func(t *testing.T) {
+		stream, _ := NewEventStream(httptest.NewRecorder(), r, WithHeartbeat(0), WithEventLogger(logger))
+		defer stream.Close()
+		if err := stream.Send("bad\nname", "", nil); err == nil {
+			t.Error("Expected an error for an event name with a line break")
+		}
+	})
+}
+
+func TestEventStreamReplay(t *testing.T) {
+	logger, _ := testEventLogger()
+	buffer := NewRingBuffer(10)
+
+	first := httptest.NewRecorder()
+	stream, _ := NewEventStream(first, httptest.NewRequest(http.MethodGet, "/progress", nil),
+		WithHeartbeat(0), WithReplayBuffer(buffer), WithEventLogger(logger))
+	for _, id := range []string{"1", "2", "3"} {
+		stream.Send("progress", id, id)
+	}
+	stream.Close()
+
+	r := httptest.NewRequest(http.MethodGet, "/progress", nil)
+	r.Header.Set("Last-Event-ID", "1")
+	second := httptest.NewRecorder()
+	stream, _ = NewEventStream(second, r, WithHeartbeat(0), WithReplayBuffer(buffer), WithEventLogger(logger))
+	stream.Close()
+
+	body := second.Body.String()
+	if strings.Contains(body, "id: 1\n") || !strings.Contains(body, "id: 2\n") || !strings.Contains(body, "id: 3\n") {
+		t.Errorf("Expected events 2 and 3 to be replayed, got %q", body)
+	}
+}
+
+func TestEventStreamHeartbeat(t *testing.T) {
+	logger, _ := testEventLogger()
+	w := httptest.NewRecorder()
+	stream, _ := NewEventStream(w, httptest.NewRequest(http.MethodGet, "/progress", nil),
+		WithHeartbeat(5*time.Millisecond), WithEventLogger(logger))
+
+	time.Sleep(30 * time.Millisecond)
+	stream.Close()
+
+	if !strings.Contains(w.Body.String(), ": heartbeat\n\n") {
+		t.Errorf("Expected heartbeat comments, got %q", w.Body.String())
+	}
+}
+
+func TestEventStreamClientDisconnect(t *testing.T) {
+	logger, logs := testEventLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/progress", nil).WithContext(ctx)
+
+	stream, _ := NewEventStream(httptest.NewRecorder(), r, WithHeartbeat(0), WithEventLogger(logger))
+	cancel()
+
+	select {
+	case <-stream.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stream to close when the client disconnects")
+	}
+
+	if !strings.Contains(logs.String(), "client disconnected") {
+		t.Errorf("Expected the disconnect to be logged, got %s", logs.String())
+	}
+}
+
+func TestEventStreamStopsWithRequest(t *testing.T) {
+	logger, _ := testEventLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/progress", nil).WithContext(ctx)
+
+	stream, err := NewEventStream(w, r, WithHeartbeat(time.Hour), WithEventLogger(logger))
+	if err != nil {
+		t.Fatalf("Expected no error on NewEventStream(), got %v", err)
+	}
+	if w.Header().Get("Connection") != "" {
+		t.Errorf("Expected no hop-by-hop Connection header, got %q", w.Header().Get("Connection"))
+	}
+
+	// The handler returned without closing the stream
+	cancel()
+	written := w.Body.Len()
+	if err := stream.Heartbeat(); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("Expected ErrStreamClosed after the request ended, got %v", err)
+	}
+	if w.Body.Len() != written {
+		t.Errorf("Expected nothing to be written after the request ended, got %q", w.Body.String())
+	}
+
+	select {
+	case <-stream.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stream to be closed")
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("Expected Close after the request ended to succeed, got %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+}
+
+func TestEventStreamNotSupported(t *testing.T) {
+	rec := httptest.NewRecorder()
+	// Hides the Flush method of the recorder
+	w := struct{ http.ResponseWriter }{rec}
+
+	_, err := NewEventStream(w, httptest.NewRequest(http.MethodGet, "/progress", nil), WithHeartbeat(0))
+	if !errors.Is(err, http.ErrNotSupported) {
+		t.Fatalf("Expected ErrNotSupported, got %v", err)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "" {
+		t.Errorf("Expected no headers to be set, got Content-Type %q", ct)
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the handler to still send an error status, got %d", rec.Code)
+	}
+}
+
+func TestEventStreamNilRequest(t *testing.T) {
+	logger, _ := testEventLogger()
+	w := httptest.NewRecorder()
+
+	stream, err := NewEventStream(w, nil, WithHeartbeat(0), WithEventLogger(logger))
+	if err != nil {
+		t.Fatalf("Expected no error on NewEventStream(), got %v", err)
+	}
+	if err := stream.Send("", "", "ready"); err != nil {
+		t.Errorf("Expected no error on Send(), got %v", err)
+	}
+	stream.Close()
+
+	if !strings.Contains(w.Body.String(), "data: ") {
+		t.Errorf("Expected an event, got %q", w.Body.String())
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	buffer := NewRingBuffer(3)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		buffer.Add(Event{ID: id})
+	}
+
+	if _, ok := buffer.Since("1"); ok {
+		t.Error("Expected evicted event 1 to be unknown")
+	}
+
+	events, ok := buffer.Since("2")
+	if !ok || len(events) != 2 || events[0].ID != "3" || events[1].ID != "4" {
+		t.Errorf("Expected events 3 and 4, got %v (ok %v)", events, ok)
+	}
+
+	events, ok = buffer.Since("4")
+	if !ok || len(events) != 0 {
+		t.Errorf("Expected no events after the latest, got %v (ok %v)", events, ok)
+	}
+}
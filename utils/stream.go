@@ -0,0 +1,346 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"slices"
+)
+
+// MediaTypeNDJSON is the media type of newline-delimited JSON streams
+const MediaTypeNDJSON = "application/x-ndjson"
+
+// StreamErrorField is the name of the field reporting an error that interrupted a stream
+const StreamErrorField = "error"
+
+// StreamErrorTrailer is the HTTP trailer set when an error interrupts a stream
+const StreamErrorTrailer = "Gecho-Stream-Error"
+
+// DefaultFlushEvery is the number of items written between flushes of a stream
+const DefaultFlushEvery = 100
+
+// StreamFormat selects how Stream writes the items
+type StreamFormat int
+
+const (
+	// StreamFormatAuto negotiates the format using the Accept header
+	StreamFormatAuto StreamFormat = iota
+	// StreamFormatEnvelope writes the standard envelope with the items as a JSON array
+	StreamFormatEnvelope
+	// StreamFormatNDJSON writes one JSON value per line
+	StreamFormatNDJSON
+)
+
+// streamOptions holds the stream-specific configuration of a response
+type streamOptions struct {
+	format     StreamFormat
+	flushEvery int
+}
+
+// WithStreamFormat sets the format used by Stream, defaults to StreamFormatAuto
+func WithStreamFormat(format StreamFormat) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.stream.format = format
+	}
+}
+
+// WithFlushEvery sets how many items Stream writes between flushes, defaults to DefaultFlushEvery
+func WithFlushEvery(n int) ResponseOption {
+	return func(rc *responseConfig) {
+		rc.stream.flushEvery = n
+	}
+}
+
+// streamedData marks the position of the streamed items among the envelope fields
+type streamedData struct{}
+
+// streamError is the value reported when an error interrupts a stream
+type streamError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// Stream writes the items of seq as they are produced, without buffering the whole result
+// The envelope format writes {"status":..,"data":[...]} while NDJSON writes one item per line,
+// chosen with WithStreamFormat or negotiated from the Accept header
+// Since the status line has already been sent, an error from seq is reported in a trailing
+// StreamErrorField, as a last NDJSON line or envelope field, and in the StreamErrorTrailer trailer
+// Streaming stops when the request context is canceled
+// The returned error is meant for logging, the response has already been written
+// ErrHeadersCommitted is returned without consuming seq when the writer has already written its headers
+// Example: utils.Stream(w, r, store.AllUsers(ctx), utils.WithFlushEvery(500))
+func Stream[T any](w http.ResponseWriter, r *http.Request, seq iter.Seq2[T, error], opts ...ResponseOption) error {
+	if w == nil {
+		panic("http.ResponseWriter is nil")
+	}
+	if HeadersWritten(w) {
+		return ErrHeadersCommitted
+	}
+
+	config := &responseConfig{
+		w:       w,
+		req:     r,
+		status:  http.StatusOK,
+		success: true,
+		message: SuccessMessage,
+		headers: make(http.Header),
+		stream:  streamOptions{flushEvery: DefaultFlushEvery},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	format := config.stream.format
+	if format == StreamFormatAuto {
+		format = StreamFormatEnvelope
+		if r != nil && prefersNDJSON(r.Header.Get("Accept")) {
+			format = StreamFormatNDJSON
+		}
+	}
+
+	contentType := MediaTypeJSON
+	if format == StreamFormatNDJSON {
+		contentType = MediaTypeNDJSON
+	}
+
+	setHeaders(w, config.headers)
+	if r != nil {
+		addVary(w, "Accept")
+	}
+
+	// The sequence is not consumed when no body will be sent
+	if !StatusAllowsBody(config.status) || (r != nil && r.Method == http.MethodHead) {
+		if StatusAllowsBody(config.status) {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(config.status)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Trailer", StreamErrorTrailer)
+	w.WriteHeader(config.status)
+
+	sw := &streamWriter{
+		buf:        bufio.NewWriter(w),
+		controller: http.NewResponseController(w),
+		flushEvery: config.stream.flushEvery,
+	}
+
+	var err error
+	if format == StreamFormatNDJSON {
+		err = sw.writeNDJSON(r, anySeq(seq))
+	} else {
+		err = sw.writeEnvelope(r, anySeq(seq), config)
+	}
+
+	if sw.failure != nil {
+		w.Header().Set(StreamErrorTrailer, sw.failure.Message)
+	}
+	return err
+}
+
+// prefersNDJSON reports whether the Accept header prefers NDJSON over any other media type
+func prefersNDJSON(accept string) bool {
+	ndjson, other := 0.0, 0.0
+	for _, ar := range parseAccept(accept) {
+		if ar.mediaType == MediaTypeNDJSON {
+			ndjson = max(ndjson, ar.quality)
+		} else {
+			other = max(other, ar.quality)
+		}
+	}
+	return ndjson > 0 && ndjson >= other
+}
+
+// streamWriter buffers the encoded items and flushes them to the client periodically
+type streamWriter struct {
+	buf        *bufio.Writer
+	controller *http.ResponseController
+	flushEvery int
+	pending    int
+	failure    *streamError
+}
+
+// flush sends the buffered output to the client
+func (sw *streamWriter) flush() error {
+	if err := sw.buf.Flush(); err != nil {
+		return err
+	}
+	sw.pending = 0
+
+	// Writers that cannot flush still receive the output once the handler returns
+	if err := sw.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// itemWritten flushes once enough items have been written since the last flush
+func (sw *streamWriter) itemWritten() error {
+	sw.pending++
+	if sw.flushEvery > 0 && sw.pending >= sw.flushEvery {
+		return sw.flush()
+	}
+	return nil
+}
+
+// each encodes every item of seq and writes it with write, stopping on the first error or a canceled context
+// Errors produced by seq or while encoding an item are recorded as the stream failure and returned
+func (sw *streamWriter) each(r *http.Request, seq iter.Seq2[any, error], write func(i int, item []byte)) error {
+	var err error
+	i := 0
+	seq(func(item any, seqErr error) bool {
+		if r != nil {
+			if err = r.Context().Err(); err != nil {
+				return false
+			}
+		}
+
+		var b []byte
+		if err = seqErr; err == nil {
+			b, err = json.Marshal(item)
+		}
+		if err != nil {
+			sw.failure = &streamError{Status: http.StatusInternalServerError, Message: InternalServerErrorMessage}
+			return false
+		}
+
+		write(i, b)
+		i++
+
+		err = sw.itemWritten()
+		return err == nil
+	})
+	return err
+}
+
+// anySeq converts a typed sequence so it can be consumed by the stream writer
+func anySeq[T any](seq iter.Seq2[T, error]) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for item, err := range seq {
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// writeValue encodes a single JSON value into the buffer
+func (sw *streamWriter) writeValue(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = sw.buf.Write(b)
+	return err
+}
+
+// writeNDJSON writes one JSON value per line, followed by an error line if seq fails
+func (sw *streamWriter) writeNDJSON(r *http.Request, seq iter.Seq2[any, error]) error {
+	err := sw.each(r, seq, func(_ int, item []byte) {
+		sw.buf.Write(item)
+		sw.buf.WriteByte('\n')
+	})
+
+	if sw.failure != nil {
+		if writeErr := sw.writeValue(map[string]any{StreamErrorField: sw.failure}); writeErr == nil {
+			sw.buf.WriteByte('\n')
+		}
+	}
+
+	return errors.Join(err, sw.flush())
+}
+
+// writeFailure writes the error field of the envelope, preceded by a comma when fields have been written
+func (sw *streamWriter) writeFailure(comma bool) {
+	if comma {
+		sw.buf.WriteByte(',')
+	}
+	sw.writeValue(StreamErrorField)
+	sw.buf.WriteByte(':')
+	sw.writeValue(sw.failure)
+}
+
+// writeEnvelope writes the envelope of the response with the items streamed into its data field
+func (sw *streamWriter) writeEnvelope(r *http.Request, seq iter.Seq2[any, error], config *responseConfig) error {
+	schema := GetEnvelopeSchema()
+	if config.schema != nil {
+		schema = *config.schema
+	}
+	if schema.DataField == OmitField {
+		schema.DataField = DefaultEnvelopeSchema().DataField
+	}
+
+	nr := NewResponse{
+		status:    config.status,
+		success:   config.success,
+		message:   config.message,
+		data:      streamedData{},
+		meta:      config.meta,
+		requestID: requestIDOf(r),
+		timestamp: getTimestamp(),
+	}
+
+	fields := schema.fields(nr)
+	reportFailure := !slices.ContainsFunc(fields, func(f envelopeField) bool {
+		return f.name == StreamErrorField
+	})
+
+	var err error
+	written := false
+	sw.buf.WriteByte('{')
+	for _, f := range fields {
+		// Fields are encoded before being written, a field that fails is left out and reported like a failing sequence
+		key, encodeErr := json.Marshal(f.name)
+		var value []byte
+		_, streamed := f.value.(streamedData)
+		if encodeErr == nil && !streamed {
+			value, encodeErr = json.Marshal(f.value)
+		}
+		if encodeErr != nil {
+			err = errors.Join(err, encodeErr)
+			if sw.failure == nil {
+				sw.failure = &streamError{Status: http.StatusInternalServerError, Message: InternalServerErrorMessage}
+			}
+			break
+		}
+
+		if written {
+			sw.buf.WriteByte(',')
+		}
+		written = true
+		sw.buf.Write(key)
+		sw.buf.WriteByte(':')
+
+		if !streamed {
+			sw.buf.Write(value)
+			continue
+		}
+
+		sw.buf.WriteByte('[')
+		err = sw.each(r, seq, func(i int, item []byte) {
+			if i > 0 {
+				sw.buf.WriteByte(',')
+			}
+			sw.buf.Write(item)
+		})
+		sw.buf.WriteByte(']')
+
+		// A client that has gone away or a broken connection leaves nothing to complete
+		if err != nil && sw.failure == nil {
+			return err
+		}
+	}
+
+	// The failure is reported once, after the fields written so far
+	// The trailer still reports it when the schema already uses the field name
+	if sw.failure != nil && reportFailure {
+		sw.writeFailure(written)
+	}
+	sw.buf.WriteString("}\n")
+
+	return errors.Join(err, sw.flush())
+}
@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// numbers yields 1 to n, failing with err afterwards when it is set
+func numbers(n int, err error) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(i, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(0, err)
+		}
+	}
+}
+
+// flushRecorder counts the flushes of a ResponseRecorder
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (fr *flushRecorder) Flush() {
+	fr.flushes++
+	fr.ResponseRecorder.Flush()
+}
+
+func TestStreamEnvelope(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+
+	if err := Stream(w, r, numbers(3, nil)); err != nil {
+		t.Fatalf("Expected no error on Stream(), got %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != MediaTypeJSON {
+		t.Errorf("Expected Content-Type %s, got %s", MediaTypeJSON, ct)
+	}
+
+	val, err := ExtractResponseBody[NewResponse](w.Result())
+	if err != nil {
+		t.Fatalf("Expected a valid envelope, got %v (%s)", err, w.Body.String())
+	}
+	if !val.Success() || val.Status() != http.StatusOK || val.Message() != "Success" {
+		t.Errorf("Unexpected envelope %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"data":[1,2,3]`) {
+		t.Errorf("Expected streamed data array, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `"error"`) {
+		t.Errorf("Expected no error field, got %s", w.Body.String())
+	}
+}
+
+func TestStreamEmpty(t *testing.T) {
+	w := httptest.NewRecorder()
+	Stream(w, nil, numbers(0, nil))
+
+	if !strings.Contains(w.Body.String(), `"data":[]`) {
+		t.Errorf("Expected empty data array, got %s", w.Body.String())
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	r.Header.Set("Accept", MediaTypeNDJSON)
+
+	Stream(w, r, numbers(3, nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != MediaTypeNDJSON {
+		t.Errorf("Expected Content-Type %s, got %s", MediaTypeNDJSON, ct)
+	}
+	if w.Body.String() != "1\n2\n3\n" {
+		t.Errorf("Expected one item per line, got %q", w.Body.String())
+	}
+
+	t.Run("ForcedFormat", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Stream(w, nil, numbers(2, nil), WithStreamFormat(StreamFormatNDJSON))
+		if w.Body.String() != "1\n2\n" {
+			t.Errorf("Expected NDJSON output, got %q", w.Body.String())
+		}
+	})
+}
+
+func TestStreamError(t *testing.T) {
+	failure := errors.New("database connection lost")
+
+	t.Run("Envelope", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := Stream(w, nil, numbers(2, failure))
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected the sequence error to be returned, got %v", err)
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected valid JSON after an error, got %v (%s)", err, w.Body.String())
+		}
+		streamErr, ok := body[StreamErrorField].(map[string]any)
+		if !ok || streamErr["message"] != InternalServerErrorMessage {
+			t.Errorf("Expected trailing error field, got %s", w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), failure.Error()) {
+			t.Errorf("Expected the cause not to be sent, got %s", w.Body.String())
+		}
+
+		if trailer := w.Result().Trailer.Get(StreamErrorTrailer); trailer != InternalServerErrorMessage {
+			t.Errorf("Expected trailer %s, got %q", StreamErrorTrailer, trailer)
+		}
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Stream(w, nil, numbers(1, failure), WithStreamFormat(StreamFormatNDJSON))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], `{"error":`) {
+			t.Errorf("Expected a trailing error line, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("UnencodableItem", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		seq := func(yield func(any, error) bool) {
+			yield(1, nil)
+			yield(func() {}, nil)
+		}
+		if err := Stream[any](w, nil, seq); err == nil {
+			t.Error("Expected an encoding error")
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected valid JSON after an encoding error, got %v (%s)", err, w.Body.String())
+		}
+		if _, ok := body[StreamErrorField]; !ok {
+			t.Errorf("Expected trailing error field, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("UnencodableMeta", func(t *testing.T) {
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		if err := Stream(w, nil, numbers(2, nil), WithMeta("callback", func() {})); err == nil {
+			t.Error("Expected an encoding error")
+		}
+		if w.flushes == 0 {
+			t.Error("Expected the envelope to be flushed")
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected valid JSON after an encoding error, got %v (%s)", err, w.Body.String())
+		}
+		if _, ok := body[StreamErrorField]; !ok {
+			t.Errorf("Expected trailing error field, got %s", w.Body.String())
+		}
+		if trailer := w.Result().Trailer.Get(StreamErrorTrailer); trailer != InternalServerErrorMessage {
+			t.Errorf("Expected trailer %s, got %q", StreamErrorTrailer, trailer)
+		}
+	})
+
+	t.Run("FailingSequenceAndMeta", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := Stream(w, nil, numbers(1, failure), WithMeta("callback", func() {}))
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected the sequence error to be returned, got %v", err)
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected valid JSON, got %v (%s)", err, w.Body.String())
+		}
+		if n := strings.Count(w.Body.String(), `"`+StreamErrorField+`":`); n != 1 {
+			t.Errorf("Expected a single error field, got %d in %s", n, w.Body.String())
+		}
+	})
+}
+
+func TestStreamFlushes(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	Stream(w, nil, numbers(10, nil), WithFlushEvery(3))
+
+	// Three periodic flushes plus the final one
+	if w.flushes != 4 {
+		t.Errorf("Expected 4 flushes, got %d", w.flushes)
+	}
+}
+
+func TestStreamStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	produced := 0
+	seq := func(yield func(int, error) bool) {
+		for i := 0; i < 100; i++ {
+			produced++
+			if i == 5 {
+				cancel()
+			}
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+
+	err := Stream(w, r, seq)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if produced != 6 {
+		t.Errorf("Expected the sequence to stop after 6 items, got %d", produced)
+	}
+}
+
+func TestPrefersNDJSON(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/x-ndjson", true},
+		{"application/json, application/x-ndjson;q=0.5", false},
+		{"application/json;q=0.5, application/x-ndjson", true},
+	}
+
+	for _, tt := range tests {
+		if got := prefersNDJSON(tt.accept); got != tt.expected {
+			t.Errorf("prefersNDJSON(%q) = %v, expected %v", tt.accept, got, tt.expected)
+		}
+	}
+}
+
+func TestStreamHEAD(t *testing.T) {
+	consumed := false
+	seq := func(yield func(int, error) bool) {
+		consumed = true
+		yield(1, nil)
+	}
+
+	w := httptest.NewRecorder()
+	Stream(w, httptest.NewRequest(http.MethodHead, "/export", nil), seq)
+
+	if consumed {
+		t.Error("Expected the sequence not to be consumed for HEAD")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no body, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,320 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes a single failed validation rule
+type FieldError struct {
+	Field   string `json:"field"`           // Dotted path using JSON names, e.g. "address.street"
+	Rule    string `json:"rule"`            // Name of the failed rule, e.g. "min"
+	Param   string `json:"param,omitempty"` // Parameter of the rule, e.g. "3"
+	Message string `json:"message"`         // Human-readable description of the failure
+	Pointer string `json:"pointer"`         // RFC 6901 JSON pointer to the value, e.g. "/address/street"
+}
+
+// Errors is the list of failed rules returned by Struct
+type Errors []FieldError
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+" "+fe.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Struct validates v using the `validate` struct tags of its fields
+// Nested structs, pointers and slices of structs are validated recursively
+// Values that are not structs are not validated
+//
+// Supported rules:
+//   - required: the value must not be the zero value
+//   - omitempty: skip the remaining rules when the value is the zero value
+//   - min=n, max=n, len=n: string length, collection length or numeric value
+//   - email: a plain email address
+//   - url: an absolute URL with a scheme and host
+//   - oneof=a b c: one of the space-separated values
+//
+// Example: `validate:"required,min=3,max=64"`
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	if err := validateStruct(rv, "", "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct validates all exported fields of a struct value
+func validateStruct(rv reflect.Value, field, pointer string, errs *Errors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		// Embedded structs without a JSON name have their fields promoted
+		if sf.Anonymous && sf.Tag.Get("json") == "" {
+			if inner := indirect(fv); inner.Kind() == reflect.Struct {
+				if err := validateStruct(inner, field, pointer, errs); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		fieldPath := name
+		if field != "" {
+			fieldPath = field + "." + name
+		}
+		fieldPointer := pointer + "/" + escapePointer(name)
+
+		if err := validateField(fv, sf.Tag.Get("validate"), fieldPath, fieldPointer, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateField applies the rules of a tag to a value and descends into nested values
+func validateField(fv reflect.Value, tag, field, pointer string, errs *Errors) error {
+	rules := parseRules(tag)
+	value := indirect(fv)
+
+	for _, r := range rules {
+		if r.name == "omitempty" {
+			if isZero(fv) {
+				return nil
+			}
+			continue
+		}
+
+		if r.name == "required" {
+			if isZero(fv) {
+				*errs = append(*errs, newFieldError(field, pointer, r, "is required"))
+				return nil
+			}
+			continue
+		}
+
+		// Remaining rules only apply to present values
+		if !value.IsValid() {
+			continue
+		}
+
+		message, err := checkRule(value, r)
+		if err != nil {
+			return fmt.Errorf("validation: field %s: %w", field, err)
+		}
+		if message != "" {
+			*errs = append(*errs, newFieldError(field, pointer, r, message))
+		}
+	}
+
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return validateStruct(value, field, pointer, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elem := indirect(value.Index(i))
+			if elem.Kind() != reflect.Struct {
+				continue
+			}
+			index := strconv.Itoa(i)
+			if err := validateStruct(elem, field+"."+index, pointer+"/"+index, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rule is a single parsed validation rule
+type rule struct {
+	name  string
+	param string
+}
+
+// parseRules splits a validate tag into rules
+func parseRules(tag string) []rule {
+	rules := make([]rule, 0)
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		rules = append(rules, rule{name: name, param: param})
+	}
+	return rules
+}
+
+// checkRule returns the failure message of a rule, or an empty string when it passes
+func checkRule(value reflect.Value, r rule) (string, error) {
+	switch r.name {
+	case "min", "max", "len":
+		return checkSize(value, r)
+	case "email":
+		s, ok := stringValue(value)
+		if !ok {
+			return "", fmt.Errorf("rule %q requires a string", r.name)
+		}
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return "must be a valid email address", nil
+		}
+	case "url":
+		s, ok := stringValue(value)
+		if !ok {
+			return "", fmt.Errorf("rule %q requires a string", r.name)
+		}
+		if u, err := url.ParseRequestURI(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL", nil
+		}
+	case "oneof":
+		options := strings.Fields(r.param)
+		if !slices.Contains(options, fmt.Sprint(value.Interface())) {
+			return "must be one of: " + strings.Join(options, ", "), nil
+		}
+	default:
+		return "", fmt.Errorf("unknown rule %q", r.name)
+	}
+	return "", nil
+}
+
+// checkSize checks the min, max and len rules against lengths or numeric values
+func checkSize(value reflect.Value, r rule) (string, error) {
+	limit, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return "", fmt.Errorf("rule %q has invalid parameter %q", r.name, r.param)
+	}
+
+	var size float64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		size, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		return "", fmt.Errorf("rule %q is not supported for %s", r.name, value.Kind())
+	}
+
+	switch {
+	case r.name == "min" && size < limit:
+		if unit != "" {
+			return "must contain at least " + r.param + unit, nil
+		}
+		return "must be at least " + r.param, nil
+	case r.name == "max" && size > limit:
+		if unit != "" {
+			return "must contain at most " + r.param + unit, nil
+		}
+		return "must be at most " + r.param, nil
+	case r.name == "len" && size != limit:
+		if unit != "" {
+			return "must contain exactly " + r.param + unit, nil
+		}
+		return "must be exactly " + r.param, nil
+	}
+	return "", nil
+}
+
+// newFieldError builds a FieldError for a failed rule
+func newFieldError(field, pointer string, r rule, message string) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    r.name,
+		Param:   r.param,
+		Message: message,
+		Pointer: pointer,
+	}
+}
+
+// jsonName returns the JSON name of a struct field, ok is false for ignored fields
+func jsonName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return sf.Name, true
+}
+
+// escapePointer escapes a reference token as described in RFC 6901
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isZero reports whether a value is missing: nil, the zero value or an empty collection
+func isZero(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// stringValue returns the value as a string when it is one
+func stringValue(v reflect.Value) (string, bool) {
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
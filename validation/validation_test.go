@@ -0,0 +1,132 @@
+package validation
+
+import (
+	stderrors "errors"
+	"testing"
+)
+
+type address struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city" validate:"required,min=2"`
+}
+
+type item struct {
+	SKU      string `json:"sku" validate:"len=8"`
+	Quantity int    `json:"quantity" validate:"min=1,max=99"`
+}
+
+type signup struct {
+	Username string   `json:"username" validate:"required,min=3,max=64"`
+	Email    string   `json:"email" validate:"required,email"`
+	Role     string   `json:"role" validate:"oneof=admin member"`
+	Website  string   `json:"website,omitempty" validate:"omitempty,url"`
+	Nickname *string  `json:"nickname" validate:"omitempty,max=5"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Address  *address `json:"address" validate:"required"`
+	Items    []item   `json:"items"`
+	internal string   `validate:"required"`
+}
+
+func TestStructValid(t *testing.T) {
+	valid := signup{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Role:     "admin",
+		Address:  &address{Street: "Main St", City: "Amsterdam"},
+		Items:    []item{{SKU: "ABCD1234", Quantity: 2}},
+	}
+
+	if err := Struct(&valid); err != nil {
+		t.Errorf("Expected no validation errors, got %v", err)
+	}
+}
+
+func TestStructInvalid(t *testing.T) {
+	nickname := "toolongnick"
+	invalid := signup{
+		Username: "al",
+		Email:    "Alice <alice@example.com>",
+		Role:     "owner",
+		Website:  "not a url",
+		Nickname: &nickname,
+		Tags:     []string{"a", "b", "c"},
+		Address:  &address{Street: "", City: "A"},
+		Items:    []item{{SKU: "ABCD1234", Quantity: 1}, {SKU: "short", Quantity: 100}},
+	}
+
+	err := Struct(invalid)
+
+	var errs Errors
+	if !stderrors.As(err, &errs) {
+		t.Fatalf("Expected validation Errors, got %v", err)
+	}
+
+	expected := map[string]string{
+		"/username":         "min",
+		"/email":            "email",
+		"/role":             "oneof",
+		"/website":          "url",
+		"/nickname":         "max",
+		"/tags":             "max",
+		"/address/street":   "required",
+		"/address/city":     "min",
+		"/items/1/sku":      "len",
+		"/items/1/quantity": "max",
+	}
+
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+
+	for _, fe := range errs {
+		if rule, ok := expected[fe.Pointer]; !ok || rule != fe.Rule {
+			t.Errorf("Unexpected error %+v", fe)
+		}
+		if fe.Message == "" {
+			t.Errorf("Expected a message for %s", fe.Pointer)
+		}
+	}
+}
+
+func TestStructFieldPath(t *testing.T) {
+	err := Struct(signup{Username: "alice", Email: "a@b.co", Role: "admin", Address: &address{City: "Paris"}})
+
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("Expected a single error, got %v", err)
+	}
+
+	if errs[0].Field != "address.street" || errs[0].Pointer != "/address/street" {
+		t.Errorf("Expected field 'address.street' and pointer '/address/street', got %+v", errs[0])
+	}
+}
+
+func TestStructRequiredPointer(t *testing.T) {
+	err := Struct(signup{Username: "alice", Email: "a@b.co", Role: "member"})
+
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 || errs[0].Field != "address" || errs[0].Rule != "required" {
+		t.Errorf("Expected missing address to fail required, got %v", err)
+	}
+}
+
+func TestStructUnknownRule(t *testing.T) {
+	type bad struct {
+		Name string `validate:"uppercase"`
+	}
+
+	err := Struct(bad{Name: "x"})
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown rule")
+	}
+
+	if _, ok := err.(Errors); ok {
+		t.Errorf("Expected a configuration error, not validation Errors")
+	}
+}
+
+func TestStructNonStruct(t *testing.T) {
+	if err := Struct(map[string]string{"a": "b"}); err != nil {
+		t.Errorf("Expected non-struct values to be ignored, got %v", err)
+	}
+}